@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+//Environment variables set for commands with ForceColor, most tools color their output
+//when not writing to a terminal if one of them is set
+var ForceColorEnv = []string{"CLICOLOR_FORCE=1", "FORCE_COLOR=1"}
+
+//parses the ANSI escape sequences in command output
+//the style set by SGR sequences carries over from line to line
+type ansiParser struct {
+	base  tcell.Style //style after a reset
+	style tcell.Style
+	mono  bool //colors are dropped, bold, underline and the other attributes are kept
+}
+
+func newANSIParser(base tcell.Style) *ansiParser {
+	return &ansiParser{base: base, style: base}
+}
+
+//gets a parser for output drawn with a style, without colors if it is monochrome
+func (s *Style) ansiParser() *ansiParser {
+	a := newANSIParser(s.Default)
+	a.mono = s.monochrome
+	return a
+}
+
+//removes the escape sequences in a line, returning the text left and the style of each of its runes
+//SGR sequences set the style, any other sequence is dropped
+func (a *ansiParser) parse(line string) (string, []tcell.Style) {
+	in := []rune(line)
+	text := []rune{}
+	styles := []tcell.Style{}
+	for i := 0; i < len(in); i++ {
+		if in[i] != 0x1b {
+			text = append(text, in[i])
+			styles = append(styles, a.style)
+			continue
+		}
+		i++
+		if i >= len(in) {
+			break
+		}
+		switch in[i] {
+		case '[':
+			//CSI: parameters and intermediate bytes up to a final byte from @ to ~
+			start := i + 1
+			for i++; i < len(in) && (in[i] < 0x40 || in[i] > 0x7e); i++ {
+			}
+			if i < len(in) && in[i] == 'm' {
+				a.sgr(string(in[start:i]))
+			}
+		case ']', 'P', 'X', '^', '_':
+			//strings, such as window titles, end with BEL or ESC \
+			for i++; i < len(in) && in[i] != 0x07; i++ {
+				if in[i] == 0x1b && i+1 < len(in) && in[i+1] == '\\' {
+					i++
+					break
+				}
+			}
+		case '(', ')', '*', '+':
+			//character set selection takes one more rune
+			i++
+		}
+	}
+	return string(text), styles
+}
+
+//applies a Select Graphic Rendition sequence, such as "1;31" for bold red
+func (a *ansiParser) sgr(params string) {
+	codes := []int{}
+	for _, p := range strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' }) {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return
+		}
+		codes = append(codes, n)
+	}
+	if len(codes) == 0 {
+		codes = []int{0}
+	}
+	baseFg, baseBg, _ := a.base.Decompose()
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; {
+		case c == 0:
+			a.style = a.base
+		case c == 1:
+			a.style = a.style.Bold(true)
+		case c == 2:
+			a.style = a.style.Dim(true)
+		case c == 4:
+			a.style = a.style.Underline(true)
+		case c == 5:
+			a.style = a.style.Blink(true)
+		case c == 7:
+			a.style = a.style.Reverse(true)
+		case c == 22:
+			a.style = a.style.Bold(false).Dim(false)
+		case c == 24:
+			a.style = a.style.Underline(false)
+		case c == 25:
+			a.style = a.style.Blink(false)
+		case c == 27:
+			a.style = a.style.Reverse(false)
+		case c >= 30 && c <= 37:
+			a.style = a.style.Foreground(tcell.Color(c - 30))
+		case c >= 90 && c <= 97:
+			a.style = a.style.Foreground(tcell.Color(c - 90 + 8))
+		case c == 39:
+			a.style = a.style.Foreground(baseFg)
+		case c >= 40 && c <= 47:
+			a.style = a.style.Background(tcell.Color(c - 40))
+		case c >= 100 && c <= 107:
+			a.style = a.style.Background(tcell.Color(c - 100 + 8))
+		case c == 49:
+			a.style = a.style.Background(baseBg)
+		case c == 38 || c == 48:
+			color, n := extendedColor(codes[i+1:])
+			if n == 0 {
+				//the rest of the sequence is not valid
+				i = len(codes)
+				break
+			}
+			i += n
+			if c == 38 {
+				a.style = a.style.Foreground(color)
+			} else {
+				a.style = a.style.Background(color)
+			}
+		}
+	}
+	if a.mono {
+		a.style = a.style.Foreground(baseFg).Background(baseBg)
+	}
+}
+
+//parses the color after a 38 or 48 code, "5;n" from the 256 color palette or "2;r;g;b"
+//returns the number of codes used, 0 if they are not valid
+func extendedColor(codes []int) (tcell.Color, int) {
+	switch {
+	case len(codes) >= 2 && codes[0] == 5 && codes[1] >= 0 && codes[1] < 256:
+		return tcell.Color(codes[1]), 2
+	case len(codes) >= 4 && codes[0] == 2:
+		return tcell.NewRGBColor(int32(codes[1]), int32(codes[2]), int32(codes[3])), 4
+	}
+	return tcell.ColorDefault, 0
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestANSIParse(t *testing.T) {
+	base := DefaultStyle().Default
+	a := newANSIParser(base)
+	text, styles := a.parse("\x1b]0;title\x07a\x1b[1;31mb\x1b[38;5;208mc\x1b[48;2;1;2;3md\x1b[2K\x1b(Be\x1b[0mf")
+	if text != "abcdef" || len(styles) != 6 {
+		t.Fatalf("escape sequences not removed: %q", text)
+	}
+	bg := func() tcell.Color { _, bg, _ := base.Decompose(); return bg }()
+	want := []tcell.Style{
+		base,
+		base.Bold(true).Foreground(tcell.ColorMaroon),
+		base.Bold(true).Foreground(tcell.Color208),
+		base.Bold(true).Foreground(tcell.Color208).Background(tcell.NewRGBColor(1, 2, 3)),
+		base.Bold(true).Foreground(tcell.Color208).Background(tcell.NewRGBColor(1, 2, 3)),
+		base,
+	}
+	for i := range want {
+		if styles[i] != want[i] {
+			t.Errorf("rune %d: unexpected style", i)
+		}
+	}
+	if _, b, _ := styles[0].Decompose(); b != bg {
+		t.Error("base background not kept")
+	}
+}
+
+func TestANSICarryOver(t *testing.T) {
+	a := newANSIParser(tcell.StyleDefault)
+	a.parse("\x1b[4;92mgreen")
+	_, styles := a.parse("still")
+	if styles[0] != tcell.StyleDefault.Underline(true).Foreground(tcell.ColorLime) {
+		t.Error("style not carried to the next line")
+	}
+	a.parse("\x1b[24;39m")
+	if _, styles = a.parse("x"); styles[0] != tcell.StyleDefault {
+		t.Error("attributes not reset")
+	}
+	if text, _ := a.parse("cut\x1b[3"); text != "cut" {
+		t.Errorf("incomplete sequence not removed: %q", text)
+	}
+}
+
+func TestShowResultANSI(t *testing.T) {
+	c := Command{
+		Title:    "Color",
+		PrintOut: true,
+		Execute: func(c *Command, ch chan string) {
+			defer close(ch)
+			ch <- "\x1b[31mred\x1b[0m plain"
+		},
+	}
+	m, s := runCommand(t, &c)
+	defer m.Quit()
+	if l := screenLine(s, 4); l != "  red plain" {
+		t.Fatalf("unexpected output %q", l)
+	}
+	if _, _, style, _ := s.GetContent(2, 4); style != m.p.style.Default.Foreground(tcell.ColorMaroon) {
+		t.Error("output color not shown")
+	}
+}
+
+func TestANSIMonochrome(t *testing.T) {
+	a := DefaultStyle().Monochrome().ansiParser()
+	_, styles := a.parse("\x1b[1;31;44mx\x1b[38;5;200;4my\x1b[38;5mz")
+	for i, st := range styles {
+		if fg, bg, _ := st.Decompose(); fg != tcell.ColorDefault || bg != tcell.ColorDefault {
+			t.Errorf("color kept on rune %d: %v %v", i, fg, bg)
+		}
+	}
+	if _, _, attr := styles[1].Decompose(); attr&tcell.AttrBold == 0 || attr&tcell.AttrUnderline == 0 {
+		t.Error("attributes dropped")
+	}
+}
+
+func TestForceColor(t *testing.T) {
+	c := Command{Cli: "printenv CLICOLOR_FORCE", ForceColor: true}
+	ch := make(chan string)
+	go OSCmdHandler(&c, ch)
+	out := ""
+	for s := range ch {
+		out += s
+	}
+	if c.Error != nil || strings.TrimSpace(out) != "1" {
+		t.Errorf("CLICOLOR_FORCE not set: %q %v", out, c.Error)
+	}
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+//Minimum contrast ratios between text and background, as defined by WCAG 2.0
+const (
+	textContrast    = 4.5
+	disableContrast = 3.0
+)
+
+//Returns a style derived from a single brand color such as "#e95420"
+//The menu background is a dark shade of the color and highlighted items use the color itself,
+//all text is checked to be readable on its background
+//Colors are truecolor, screens with less colors get the nearest ones in their palette
+func BrandStyle(hex string) (*Style, error) {
+	base, err := colorful.Hex(hex)
+	if err != nil {
+		return nil, err
+	}
+	h, c, _ := base.Hcl()
+	bg := colorful.Hcl(h, math.Min(c, 0.2), 0.12).Clamped()
+	menu := colorful.Hcl(h, math.Min(c, 0.1), 0.85).Clamped()
+
+	style := func(fg, bg colorful.Color) tcell.Style {
+		return tcell.StyleDefault.Foreground(tcellColor(fg)).Background(tcellColor(bg))
+	}
+	return &Style{
+		Hightlight: style(readable(base, base, textContrast), base).Bold(true),
+		Default:    style(readable(colorful.Hcl(h, 0.05, 0.95).Clamped(), bg, textContrast), bg),
+		Menu:       style(readable(colorful.Hcl(h, 0.3, 0.2).Clamped(), menu, textContrast), menu),
+		H1:         style(readable(colorful.Hcl(h, c, 0.75).Clamped(), bg, textContrast), bg).Bold(true),
+		Input:      style(readable(colorful.Hcl(math.Mod(h+180, 360), 0.5, 0.8).Clamped(), bg, textContrast), bg),
+		Disable:    style(readable(colorful.Hcl(h, 0.05, 0.55).Clamped(), bg, disableContrast), bg),
+		Indent:     2,
+	}, nil
+}
+
+//returns fg, lightened or darkened until it has the given contrast with bg
+//black or white are used when no shade of fg is enough
+func readable(fg, bg colorful.Color, min float64) colorful.Color {
+	h, c, l := fg.Hcl()
+	step := 0.05
+	if luminance(bg) > 0.18 {
+		step = -step
+	}
+	for contrastRatio(fg, bg) < min && l >= 0 && l <= 1 {
+		l += step
+		fg = colorful.Hcl(h, c, l).Clamped()
+	}
+	if contrastRatio(fg, bg) >= min {
+		return fg
+	}
+	black, white := colorful.Color{}, colorful.Color{R: 1, G: 1, B: 1}
+	if contrastRatio(black, bg) > contrastRatio(white, bg) {
+		return black
+	}
+	return white
+}
+
+//WCAG contrast ratio, from 1 to 21
+func contrastRatio(a, b colorful.Color) float64 {
+	la, lb := luminance(a), luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+//WCAG relative luminance
+func luminance(c colorful.Color) float64 {
+	r, g, b := c.LinearRgb()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+func tcellColor(c colorful.Color) tcell.Color {
+	r, g, b := c.RGB255()
+	return tcell.NewRGBColor(int32(r), int32(g), int32(b))
+}
@@ -0,0 +1,178 @@
+// Command tui runs a menu defined in a menu file, so a menu can be shipped next to the
+// scripts it runs without writing any Go:
+//
+//  tui run ops.yaml
+//
+// The same commands can be run without a terminal, for CI or cron, by passing their
+// breadcrumb path. Output is streamed to stdout, error output to stderr, and tui exits
+// with the command status:
+//
+//  tui exec -arg version=1.2.3 ops.json "Ops > Deploy"
+//
+// Relative Cli paths in the file are resolved from the directory holding the file
+// Menu files are JSON, or YAML when their name ends in .yaml or .yml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/vtuson/tui"
+)
+
+const usage = `usage: tui <command> [arguments]
+
+menufile is a JSON file, or a YAML file ending in .yaml or .yml
+
+commands:
+  run [-theme name|file] <menufile>
+                           shows the menu defined in menufile, with a built-in theme
+                           (classic, dark, light, high-contrast) or a theme file
+  exec [flags] <menufile> <path>
+                           runs the command at path (e.g. "Ops > Deploy") without a terminal
+
+exec flags:
+  -arg name=value          sets an argument value, can be repeated
+  -args file.json          reads argument values from a JSON object
+  -env prefix              reads argument values from environment variables named
+                           prefix+NAME (default "TUI_ARG_")
+
+argument values from -arg override the environment, which overrides -args
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = run(os.Args[2:])
+	case "exec":
+		err = execute(os.Args[2:])
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "tui: unknown command %q\n", os.Args[1])
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		msg := err.Error()
+		//errors from the tui package already start with its name
+		if !strings.HasPrefix(msg, "tui: ") {
+			msg = "tui: " + msg
+		}
+		fmt.Fprintln(os.Stderr, msg)
+		if e, ok := err.(*tui.PanicError); ok {
+			os.Stderr.Write(e.Stack)
+		}
+		os.Exit(exitStatus(err))
+	}
+}
+
+//gets the exit status for an error, keeping the status of failed commands
+func exitStatus(err error) int {
+	if e, ok := err.(*exec.ExitError); ok {
+		if ws, ok := e.Sys().(syscall.WaitStatus); ok && ws.ExitStatus() > 0 {
+			return ws.ExitStatus()
+		}
+	}
+	return 1
+}
+
+//flag.Value collecting repeated -arg name=value flags
+type argFlags tui.ArgValues
+
+func (a argFlags) String() string {
+	return ""
+}
+
+func (a argFlags) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	a[kv[0]] = kv[1]
+	return nil
+}
+
+//loads a menu file and shows it until the user exits
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	theme := fs.String("theme", "", "")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("run expects a single menu file")
+	}
+	f, err := tui.ReadMenuFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var style *tui.Style
+	if *theme != "" {
+		style, err = tui.LoadTheme(*theme)
+	} else {
+		style, err = f.Style()
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(f.Dir); err != nil {
+		return err
+	}
+
+	menu := tui.NewMenu(style)
+	f.Apply(menu)
+	return menu.Run()
+}
+
+//runs a single command from a menu file without a terminal
+func execute(args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	argv := argFlags{}
+	fs.Var(argv, "arg", "")
+	argsFile := fs.String("args", "", "")
+	prefix := fs.String("env", "TUI_ARG_", "")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("exec expects a menu file and a command path")
+	}
+
+	f, err := tui.ReadMenuFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	menu := &tui.Menu{}
+	f.Apply(menu)
+	c := menu.FindCommand(fs.Arg(1))
+	if c == nil {
+		return fmt.Errorf("no command %q in %s", fs.Arg(1), fs.Arg(0))
+	}
+
+	values := tui.ArgValues{}
+	if *argsFile != "" {
+		if values, err = tui.ReadArgValues(*argsFile); err != nil {
+			return err
+		}
+	}
+	values.FromEnv(c, *prefix)
+	for k, v := range argv {
+		values[k] = v
+	}
+	if err := c.SetArgs(values); err != nil {
+		return err
+	}
+
+	if err := os.Chdir(f.Dir); err != nil {
+		return err
+	}
+	return tui.RunHeadless(c, os.Stdout, os.Stderr)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+//runs the exec example of the README from the root of the repository
+func TestExecReadmeExample(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	err = execute([]string{"-arg", "second=foo", "-arg", "first=true", "-arg", "third=true", "sampleapp/menu.json", "Test > Args CLI"})
+	if err != nil {
+		t.Fatalf("readme example failed: %v", err)
+	}
+}
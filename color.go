@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"os"
+
+	"github.com/gdamore/tcell"
+)
+
+//Returns the style to draw with on a screen, mapped to the colors it supports
+//Setting the NO_COLOR environment variable turns colors off, see https://no-color.org
+func screenStyle(s tcell.Screen, style *Style) *Style {
+	if os.Getenv("NO_COLOR") != "" {
+		return style.Monochrome()
+	}
+	return style.ForColors(s.Colors())
+}
+
+//Returns a copy of the style with its colors mapped to the nearest ones in a palette
+//of the given size, as reported by tcell.Screen.Colors
+//Text that ends up in the same color as its background is switched to black or white
+//Screens with less than 8 colors get the Monochrome style
+func (s *Style) ForColors(colors int) *Style {
+	if colors < 8 {
+		return s.Monochrome()
+	}
+	d := *s
+	if colors >= 1<<24 {
+		return &d
+	}
+	palette := make([]tcell.Color, colors)
+	for i := range palette {
+		palette[i] = tcell.Color(i)
+	}
+	for _, st := range d.styles() {
+		*st = degrade(*st, palette)
+	}
+	return &d
+}
+
+//Returns a copy of the style without colors, highlighting with reverse and bold
+func (s *Style) Monochrome() *Style {
+	d := *s
+	for _, st := range d.styles() {
+		*st = st.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault)
+	}
+	d.Hightlight = d.Hightlight.Reverse(true).Bold(true)
+	d.Menu = d.Menu.Reverse(true)
+	d.H1 = d.H1.Bold(true)
+	d.Disable = d.Disable.Dim(true)
+	d.monochrome = true
+	return &d
+}
+
+//all the tcell styles in the style
+func (s *Style) styles() []*tcell.Style {
+	return []*tcell.Style{&s.Default, &s.Hightlight, &s.Menu, &s.H1, &s.Input, &s.Disable}
+}
+
+//maps both colors of a tcell style to a palette
+func degrade(st tcell.Style, palette []tcell.Color) tcell.Style {
+	fg, bg, _ := st.Decompose()
+	fg, bg = nearest(fg, palette), nearest(bg, palette)
+	if fg == bg && fg != tcell.ColorDefault {
+		fg = contrast(bg, palette)
+	}
+	return st.Foreground(fg).Background(bg)
+}
+
+//returns the closest color in the palette
+func nearest(c tcell.Color, palette []tcell.Color) tcell.Color {
+	if c == tcell.ColorDefault || (c >= 0 && int(c) < len(palette)) {
+		return c
+	}
+	return tcell.FindColor(c, palette)
+}
+
+//returns black or the palette's white, whichever reads better on bg
+func contrast(bg tcell.Color, palette []tcell.Color) tcell.Color {
+	white := tcell.ColorSilver
+	if len(palette) > int(tcell.ColorWhite) {
+		white = tcell.ColorWhite
+	}
+	r, g, b := bg.RGB()
+	if 299*r+587*g+114*b > 128000 {
+		return tcell.ColorBlack
+	}
+	return white
+}
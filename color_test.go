@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestForColors(t *testing.T) {
+	s := DefaultStyle().ForColors(16)
+	for _, st := range s.styles() {
+		fg, bg, _ := st.Decompose()
+		if fg >= 16 || bg >= 16 {
+			t.Errorf("color out of palette: %v %v", fg, bg)
+		}
+		if fg == bg {
+			t.Errorf("text is not readable: %v on %v", fg, bg)
+		}
+	}
+	if _, bg, _ := s.Default.Decompose(); bg != tcell.ColorNavy {
+		t.Errorf("expected navy background got %v", bg)
+	}
+	if s.Indent != DefaultStyle().Indent {
+		t.Error("indent not kept")
+	}
+	//light menu and background collapse to white on 8 colors
+	s = LightStyle().ForColors(8)
+	if fg, bg, _ := s.Menu.Decompose(); fg == bg {
+		t.Errorf("menu is not readable: %v on %v", fg, bg)
+	}
+}
+
+func TestForColorsTruecolor(t *testing.T) {
+	in := &Style{Default: tcell.StyleDefault.Foreground(tcell.NewHexColor(0x123456))}
+	if s := in.ForColors(1 << 24); s.Default != in.Default {
+		t.Error("truecolor style changed")
+	}
+	if fg, _, _ := in.ForColors(256).Default.Decompose(); fg >= 256 {
+		t.Errorf("expected palette color got %v", fg)
+	}
+}
+
+func TestMonochrome(t *testing.T) {
+	s := DefaultStyle().ForColors(2)
+	for _, st := range s.styles() {
+		if fg, bg, _ := st.Decompose(); fg != tcell.ColorDefault || bg != tcell.ColorDefault {
+			t.Errorf("expected no colors got %v %v", fg, bg)
+		}
+	}
+	if _, _, attr := s.Hightlight.Decompose(); attr&tcell.AttrReverse == 0 {
+		t.Error("highlight is not reversed")
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+	s := tcell.NewSimulationScreen("")
+	p, err := NewPrintingScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	if fg, bg, _ := p.style.Default.Decompose(); fg != tcell.ColorDefault || bg != tcell.ColorDefault {
+		t.Errorf("colors used with NO_COLOR: %v %v", fg, bg)
+	}
+}
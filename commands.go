@@ -15,9 +15,13 @@ package tui
 import (
 	"fmt"
 	"github.com/gdamore/tcell"
+	"io"
 	"os"
 	"os/exec"
+	"runtime/debug"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 // Defines a basic Command object
@@ -30,6 +34,7 @@ type Command struct {
 	Description string
 	Cli         string
 	Execute     HandlerCommand
+	Hotkey      Hotkey
 	Args        []Argument
 	Optional    bool
 	Selected    bool
@@ -39,8 +44,16 @@ type Command struct {
 	breadCrum   string
 	Disable     bool
 	Status      string
-	bufferOut   []string
 	PrintOut    bool
+	ForceColor  bool       //sets ForceColorEnv so the command colors its output
+	Interactive bool       //runs Cli in a terminal shown in the menu, for commands that prompt the user
+	Suspend     bool       //runs Cli with the terminal of the menu, for full screen programs like vim or less
+	WaitKey     bool       //with Suspend, waits for a key once Cli exits so its output can be read
+	Prompts     bool       //lets Cli ask the user questions over fd 3, see servePrompts
+	ran         bool       //has run at least once, its Error is the last result
+	Events      []Event    //text and events from the handler in the last run, see Emit
+	Results     []Result   //results set by the handler in the last run
+	events      chan Event //delivers the events emitted by the handler to the menu
 }
 
 //Argument can be a flag (IsFlag) or a Envar (if defined). If IsFalg is false the Name is passed without a - appended
@@ -69,16 +82,44 @@ type Menu struct {
 	Commands      []Command
 	Cursor        int
 	BottomBar     bool
+	Mouse         bool     //enables clicking and scrolling with the mouse
+	Keymap        Keymap   //keys for each action, DefaultKeymap if nil
+	Spinner       []string //frames of the spinner shown while commands run, DefaultSpinner if nil
+	SplitPane     bool     //shows the highlighted command details next to the list on wide screens
 	BottomBarText string   //text for default top menu
+	FilterText    string   //text for the top menu while filtering commands
 	BackText      string   //text for back text on command
 	BoolText      string   //text when an arg is a bool
 	ValueText     string   //text when an arg is a value string
-	Wait          chan int //channel to wait completion
+	ChoiceText    string   //text when a running command asks to choose an answer
+	ArgsText      string   //heading for the arguments in the split pane
+	CliText       string   //heading for the command line in the split pane
+	StatusText    string   //heading for the last status in the split pane
+	TerminalText  string   //text for the bottom bar while an interactive command runs
+	WaitKeyText   string   //text shown after a Suspend command with WaitKey exits
+	Wait          chan int //closed when EventManager completes, not needed with Run
 	p             *Printing
 	breadCrum     string
 	argIndex      int
-	enableScape   bool
 	runeBuffer    []rune
+	view          view
+	command       *Command     //command shown when not in the menu view
+	progress      *ProgressBar //progress shown while command runs
+	term          *terminal    //screen of the running interactive command
+	pty           *os.File     //pseudo terminal of the running interactive command
+	busy          int32        //set while a command runs, accessed atomically
+	scroll        int          //first command shown
+	resultScroll  int          //first line of output shown
+	rows          map[int]int  //command shown on each screen row
+	buttons       []button     //buttons shown in the bottom bar
+	help          bool         //help overlay is open
+	prompt        *prompt      //question asked by the running command
+	promptCursor  int          //choice highlighted in the prompt
+	filtering     bool         //filter line is open
+	filter        []rune       //text typed to filter commands
+	mouseDown     bool
+	lastClick     time.Time
+	err           error //stops the event loop, returned by Run
 }
 
 //gets a breadcrum for a command
@@ -125,24 +166,109 @@ func (m *Menu) printPageHearder(title string, desc string) {
 
 //OS execution default handler
 //Error in command is updated in completion
+//Lines written by Cli that are workflow commands, like ::progress 40, are emitted as events
+//With Prompts set Cli can ask the user questions over fd 3, see servePrompts
 func OSCmdHandler(c *Command, ch chan string) {
-	formattedArgs := []string{}
 	c.Error = nil
 	cliArray := strings.Split(c.Cli, " ")
-	formattedArgs = cliArray[1:]
 
 	defer close(ch)
+	c.setEnv()
+
+	cmd := exec.Command(cliArray[0], c.cliArgs()...)
+	if c.ForceColor {
+		cmd.Env = append(os.Environ(), ForceColorEnv...)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		c.Error = err
+		return
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		c.Error = err
+		return
+	}
+	var side, child *os.File
+	if c.Prompts {
+		side, child, err = openSideChannel()
+		if err != nil {
+			c.Error = err
+			return
+		}
+		defer side.Close()
+		cmd.ExtraFiles = []*os.File{child}
+	}
+	c.Error = cmd.Start()
+	if child != nil {
+		child.Close()
+	}
+	if c.Error != nil {
+		return
+	}
+	//prompts end once the command and its children close fd 3
+	served := make(chan struct{})
+	if side != nil {
+		go func() {
+			c.servePrompts(side)
+			close(served)
+		}()
+	} else {
+		close(served)
+	}
+	//error output is emitted as EventStderr while the output is read
+	errDone := make(chan struct{})
+	go func() {
+		readChunks(stderr, func(text string) {
+			c.Emit(Event{Kind: EventStderr, Text: text})
+		})
+		close(errDone)
+	}()
+	w := &workflowParser{c: c, ch: ch}
+	readChunks(stdout, w.write)
+	w.flush()
+	<-errDone
+
+	c.Error = cmd.Wait()
+	<-served
+}
+
+//reads r until it ends, passing each chunk read to f
+func readChunks(r io.Reader, f func(string)) {
+	end := false
+	p := make([]byte, 1024)
+	for !end {
+		n, err := r.Read(p)
+		if n > 0 {
+			f(string(p[:n]))
+		}
+		end = err != nil
+	}
+}
+
+//sets the environment variables of the Args that are Envars
+func (c *Command) setEnv() {
 	for _, a := range c.Args {
-		if a.Envar != "" {
-			if a.IsBoolean {
-				if a.Valuebool {
-					os.Setenv(a.Envar, "true")
-				} else {
-					os.Unsetenv(a.Envar)
-				}
+		if a.Envar == "" {
+			continue
+		}
+		if a.IsBoolean {
+			if a.Valuebool {
+				os.Setenv(a.Envar, "true")
 			} else {
-				os.Setenv(a.Envar, a.Value)
+				os.Unsetenv(a.Envar)
 			}
+		} else {
+			os.Setenv(a.Envar, a.Value)
+		}
+	}
+}
+
+//gets the arguments the OSCmdHandler passes to Cli, from Cli itself and the Args that are not Envars
+func (c *Command) cliArgs() []string {
+	formattedArgs := strings.Split(c.Cli, " ")[1:]
+	for _, a := range c.Args {
+		if a.Envar != "" {
 			continue
 		}
 		if a.IsBoolean {
@@ -152,65 +278,139 @@ func OSCmdHandler(c *Command, ch chan string) {
 				} else {
 					formattedArgs = append(formattedArgs, a.Name)
 				}
-			} else {
-				continue
 			}
 		} else {
 			formattedArgs = append(formattedArgs, "-"+a.Name, a.Value)
 		}
 	}
+	return formattedArgs
+}
 
-	cmd := exec.Command(cliArray[0], formattedArgs...)
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		c.Error = err
-		return
+//gets the command line run by the OSCmdHandler with the current argument values
+//environment variables set from Args go first, as in a shell
+func (c *Command) commandLine() string {
+	line := []string{}
+	for _, a := range c.Args {
+		if a.Envar == "" {
+			continue
+		}
+		if !a.IsBoolean {
+			line = append(line, a.Envar+"="+shellQuote(a.Value))
+		} else if a.Valuebool {
+			line = append(line, a.Envar+"=true")
+		}
 	}
-	c.Error = cmd.Start()
-	if c.Error != nil {
-		return
+	line = append(line, strings.Split(c.Cli, " ")[0])
+	for _, a := range c.cliArgs() {
+		line = append(line, shellQuote(a))
 	}
-	end := false
-	for !end {
-		p := make([]byte, 1)
-		if _, err := stdout.Read(p); err == nil {
-			ch <- string(p)
-		} else {
-			end = true
-		}
+	return strings.Join(line, " ")
+}
+
+//quotes a value for a shell when it is empty or has special characters
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`*?&;|<>()#~") {
+		return s
 	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
 
-	c.Error = cmd.Wait()
+//Error set on a command when its handler panics
+type PanicError struct {
+	Value interface{}
+	Stack []byte
 }
 
-//Run commands and waits to complete, then calls menu ShowResult
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+//calls the command handler, a panic in the handler is recovered and set as the command Error
+func execute(c *Command, ch chan string) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.Error = &PanicError{Value: r, Stack: debug.Stack()}
+			//the handler may or may not have closed the channel before panicking
+			defer func() { recover() }()
+			close(ch)
+		}
+	}()
+	c.Execute(c, ch)
+}
+
+//Starts running a command and shows its progress, the handler runs in its own goroutine
+//and its output is delivered to the event loop, which calls ShowResult once it completes
+//A panic in the handler does not stop the menu, it is shown as the command Error with its stack
+//Interactive and Suspend commands take the terminal instead, see runInteractive and runSuspended
 func (m *Menu) RunCommand(c *Command) {
+	if c.Interactive {
+		m.runInteractive(c)
+		return
+	}
+	if c.Suspend {
+		m.runSuspended(c)
+		return
+	}
 	if c.Execute == nil {
 		c.Execute = OSCmdHandler
 	}
-	if c.PrintOut {
-		c.bufferOut = []string{}
+	c.resetEvents()
+	c.Error = nil
+	m.resultScroll = 0
+	m.view = viewRunning
+	m.command = c
+	atomic.StoreInt32(&m.busy, 1)
+
+	m.progress = NewProgressBar(m.p)
+	if m.Spinner != nil {
+		m.progress.Frames = m.Spinner
 	}
+	m.drawRunning()
+	m.progress.Start()
+
+	s := m.p.Screen()
 	ch := make(chan string)
-	go c.Execute(c, ch)
+	go execute(c, ch)
+	go func() {
+		forward(ch, c.events, func(e Event) {
+			s.PostEventWait(newEventOutput(c, e))
+		})
+		s.PostEventWait(newEventDone(c))
+	}()
+}
 
-	pb := NewProgressBar(m.p)
-	go pb.Start()
+//Reports whether a command is running
+func (m *Menu) Busy() bool {
+	return atomic.LoadInt32(&m.busy) == 1
+}
 
-	for ok := true; ok; {
-		var b string
-		b, ok = <-ch
-		if ok {
-			c.bufferOut = append(c.bufferOut, b)
-		}
+//called from the event loop when the running command completes
+func (m *Menu) commandDone(c *Command) {
+	atomic.StoreInt32(&m.busy, 0)
+	c.ran = true
+	if m.view == viewTerminal {
+		m.terminalDone(c)
+		return
 	}
-	pb.Stop()
+	m.progress.Stop()
 	m.ShowResult(c)
 }
 
+//draws the page shown while a command runs
+func (m *Menu) drawRunning() {
+	c := m.command
+	m.p.Clear()
+	m.printPageHearder(c.BreadCrum(), c.Description)
+	m.progress.Draw()
+	//no buttons while the command runs
+	m.buttons = nil
+	m.p.Show()
+}
+
 //Displays result of running a command, using test Fail and Success, plus adds error message for Fail
 func (m *Menu) ShowResult(c *Command) {
-	m.enableScape = true
+	m.view = viewResult
+	m.command = c
 
 	if c.Error != nil {
 		m.p.Clear()
@@ -220,22 +420,62 @@ func (m *Menu) ShowResult(c *Command) {
 		m.printPageHearder(c.BreadCrum(), "Success! "+c.Success)
 	}
 
-	if c.PrintOut {
-		tmpOut := strings.Join(c.bufferOut, "")
-		tmpOut = strings.Replace(tmpOut, "\r", "\n", -1)
-		output := strings.Split(tmpOut, "\n")
-		for _, l := range output {
-			m.p.Putln(l, false)
+	//output longer than the screen is paged, scrolled with the arrow and page keys
+	lines, disabled := resultLines(c)
+	limit := m.lastRow()
+	if max := len(lines) - (limit - m.p.Cursor); m.resultScroll > max {
+		m.resultScroll = max
+	}
+	if m.resultScroll < 0 {
+		m.resultScroll = 0
+	}
+	//colors set by escape sequences in the output carry over from the lines scrolled out
+	ansi := m.p.style.ansiParser()
+	for i := 0; i < m.resultScroll && i < disabled; i++ {
+		ansi.parse(lines[i])
+	}
+	for i := m.resultScroll; i < len(lines) && m.p.Cursor < limit; i++ {
+		if i >= disabled {
+			m.p.PutlnDisable(lines[i])
+		} else {
+			m.p.putlnStyled(ansi.parse(lines[i]))
 		}
 	}
 
 	if m.BottomBar {
 		m.p.BottomBar(m.BackText)
 	}
+	m.drawButtons(buttonBack)
 	m.p.Show()
 
 }
 
+//gets the lines shown as the result of a command, lines from disabled on are shown in disabled style
+func resultLines(c *Command) ([]string, int) {
+	lines := []string{}
+	if c.PrintOut {
+		tmpOut := strings.Replace(c.output(), "\r", "\n", -1)
+		lines = strings.Split(tmpOut, "\n")
+	}
+	if len(c.Results) > 0 {
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+		for _, r := range c.Results {
+			lines = append(lines, r.Key+": "+r.Value)
+		}
+	}
+	disabled := len(lines)
+
+	if e, ok := c.Error.(*PanicError); ok {
+		lines = append(lines, "")
+		for _, l := range strings.Split(string(e.Stack), "\n") {
+			lines = append(lines, strings.Replace(l, "\t", "    ", -1))
+		}
+	}
+	return lines, disabled
+}
+
 //Moves menu to the Next Argument in a Command
 func (m *Menu) NextArgument() {
 	m.argIndex++
@@ -244,53 +484,83 @@ func (m *Menu) NextArgument() {
 }
 
 //Displays a command in the screen as incated by Cursor in Menu
+//The command runs once all its arguments have been set
 func (m *Menu) ShowCommand() {
-	if m.Cursor >= len(m.Commands) {
-		fmt.Println("Cursor exceed array")
+	c := m.CurrentCommand()
+	if c == nil {
 		return
 	}
-	c := m.Commands[m.Cursor]
-	m.p.Clear()
-
-	runNow := (c.Args == nil || len(c.Args) == 0 || m.argIndex >= len(c.Args))
-
 	c.breadCrum = m.BreadCrum()
-	if !runNow {
-		m.printPageHearder(c.BreadCrum()+" > "+c.Args[m.argIndex].Name, c.Description)
-		m.p.Putln(c.Args[m.argIndex].Title+":", false)
-		m.p.Putln(c.Args[m.argIndex].Description, false)
-		if c.Args[m.argIndex].IsBoolean {
-			m.p.BottomBar(m.BoolText)
-		} else {
-			m.p.BottomBar(m.ValueText)
-			m.p.PutEcho(string([]rune{tcell.RuneBlock}), m.p.style.Input)
-		}
-
-	} else {
-		m.enableScape = false
-		m.printPageHearder(c.BreadCrum(), c.Description)
+	m.command = c
+	if m.argIndex < len(c.Args) {
+		m.view = viewArgument
+		m.drawArgument()
+		return
 	}
+	m.RunCommand(c)
+}
 
-	m.p.Show()
-
-	if runNow {
-		m.RunCommand(&c)
+//draws the prompt for the current argument
+func (m *Menu) drawArgument() {
+	c := m.command
+	a := c.Args[m.argIndex]
+	m.p.Clear()
+	m.printPageHearder(c.BreadCrum()+" > "+a.Name, c.Description)
+	m.p.Putln(a.Title+":", false)
+	m.p.Putln(a.Description, false)
+	bar := m.BoolText
+	if !a.IsBoolean {
+		bar = m.ValueText
+		m.p.PutEcho(string(append(m.runeBuffer, tcell.RuneBlock)), m.p.style.Input)
 	}
-
+	if m.BottomBar {
+		m.p.BottomBar(bar)
+	}
+	m.drawButtons(buttonBack)
+	m.p.Show()
 }
 
 //Show Menu
+//Menus longer than the screen scroll to keep the cursor visible
 func (m *Menu) Show() {
+	m.view = viewMenu
 	m.p.Clear()
 	m.printPageHearder(m.BreadCrum(), m.Description)
-	for i, c := range m.Commands {
-		title := c.Title
+	if m.filtering {
+		m.p.PutEcho("/"+string(append(m.filter, tcell.RuneBlock)), m.p.style.Input)
+		m.p.Return()
+		m.p.Return()
+	}
+	limit := m.lastRow()
+	items := m.visible()
+	m.scroll = scrollTo(m.scroll, m.cursorPos(items), limit-m.p.Cursor)
+	top := m.p.Cursor
+	split := m.splitColumn()
+	if split > 0 {
+		m.p.Pane(0, split)
+	}
+	m.rows = map[int]int{}
+	for pos := m.scroll; pos < len(items) && m.p.Cursor < limit; pos++ {
+		i := items[pos].index
+		c := m.Commands[i]
+		for y := m.p.Cursor; y < limit; y++ {
+			//rows are assigned before drawing, long titles wrap over the next ones
+			m.rows[y] = i
+		}
+		title, hotkey := c.hotkeyTitle()
+		marked := map[int]bool{hotkey: true}
+		offset := 0
 		if c.Optional {
 			check := "[ ]"
 			if c.Selected {
 				check = "[x]"
 			}
 			title = check + " " + title
+			offset = len(check) + 1
+			marked = map[int]bool{hotkey + offset: hotkey >= 0}
+		}
+		for r := range items[pos].marked {
+			marked[r+offset] = true
 		}
 		status := ""
 		if c.Status != "" {
@@ -300,13 +570,48 @@ func (m *Menu) Show() {
 			m.p.PutlnDisable(title + status)
 			continue
 		}
-		m.p.Putln(title+status, i == m.Cursor)
+		m.p.PutlnMarked(title+status, i == m.Cursor, marked)
+	}
+	for y := m.p.Cursor; y < limit; y++ {
+		delete(m.rows, y)
+	}
+	if split > 0 && m.cursorPos(items) >= 0 {
+		m.drawPreview(split, top, limit)
 	}
+	m.p.Pane(0, 0)
 	if m.BottomBar {
-		m.p.BottomBar(m.BottomBarText)
+		if m.filtering {
+			m.p.BottomBar(m.FilterText)
+		} else {
+			m.p.BottomBar(m.BottomBarText)
+		}
 	}
+	m.drawButtons(buttonRun, buttonQuit)
 	m.p.Show()
 }
+
+//gets the first row taken by the bottom bar, or the screen height without it
+func (m *Menu) lastRow() int {
+	_, h := m.p.Screen().Size()
+	if m.BottomBar {
+		h--
+	}
+	return h
+}
+
+//gets the first item to show so that index is visible in a list of rows
+func scrollTo(scroll, index, rows int) int {
+	if index < scroll {
+		scroll = index
+	}
+	if rows > 0 && index >= scroll+rows {
+		scroll = index - rows + 1
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	return scroll
+}
 func (m *Menu) CurrentCommand() *Command {
 	if m.Cursor < len(m.Commands) {
 		return &m.Commands[m.Cursor]
@@ -318,144 +623,83 @@ func (m *Menu) CurrentCommand() *Command {
 
 //Leaves a menu
 func (m *Menu) Quit() {
-	m.p.s.Fini()
+	if !m.p.suspended {
+		m.p.s.Fini()
+	}
 }
 
 //Moves to the next command in a list
 func (m *Menu) Next() {
-	if m.Cursor < len(m.Commands)-1 {
-		m.Cursor++
-	} else {
-		m.Cursor = 0
-	}
+	m.moveCursor(1, true)
 	m.p.Clear()
 	m.Show()
 }
 
 //Moves back to the prev command
 func (m *Menu) Prev() {
-	if m.Cursor > 0 {
-		m.Cursor--
-	} else {
-		m.Cursor = len(m.Commands) - 1
-	}
+	m.moveCursor(-1, true)
 	m.p.Clear()
 	m.Show()
 }
 
-//Returns an initialised default Menu
+//moves the cursor through the commands shown, wrapping around the ends if wrap is set
+func (m *Menu) moveCursor(delta int, wrap bool) {
+	items := m.visible()
+	if len(items) == 0 {
+		return
+	}
+	pos := m.cursorPos(items) + delta
+	switch {
+	case pos < 0 && wrap:
+		pos = len(items) - 1
+	case pos < 0:
+		pos = 0
+	case pos >= len(items) && wrap:
+		pos = 0
+	case pos >= len(items):
+		pos = len(items) - 1
+	}
+	m.Cursor = items[pos].index
+}
+
+//Returns an initialised default Menu, exits if the terminal cannot be used
 func NewMenu(style *Style) *Menu {
-	channel := make(chan int)
 	s, e := tcell.NewScreen()
+	if e == nil {
+		var m *Menu
+		if m, e = NewMenuWithScreen(s, style); e == nil {
+			return m
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%v\n", e)
+	os.Exit(1)
+	return nil
+}
+
+//Returns an initialised default Menu drawing on the given screen, for example a
+//tcell.SimulationScreen in tests. The screen is initialised by the menu
+func NewMenuWithScreen(s tcell.Screen, style *Style) (*Menu, error) {
+	channel := make(chan int)
+	p, e := NewPrintingScreen(s, style)
 	if e != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", e)
-		os.Exit(1)
+		return nil, e
 	}
-	p := NewPrinting(s, style)
 	return &Menu{
 		BottomBar:     true,
 		BottomBarText: "Press ESC to exit",
+		FilterText:    "Type to filter, ENTER to run the selected command, ESC to clear",
 		BackText:      "Press ESC to go back",
 		BoolText:      "Press Y for yes or N for No, ESC to Cancel",
 		ValueText:     "Type your answer and press ENTER to continue, or ESC to Cancel",
+		ChoiceText:    "Choose an answer with the arrows or its number and press ENTER, or ESC to Cancel",
+		ArgsText:      "Arguments:",
+		CliText:       "Command line:",
+		StatusText:    "Status:",
+		TerminalText:  "Keys go to the running command",
+		WaitKeyText:   "Press any key to return to the menu",
 		Wait:          channel,
+		Keymap:        DefaultKeymap(),
 		p:             p,
 		runeBuffer:    []rune{},
-	}
-}
-
-//Handles key events for commnands
-func (menu *Menu) EventCommandManager() {
-	for {
-		ev := menu.p.Screen().PollEvent()
-		cmd := menu.CurrentCommand()
-		var arg *Argument
-		if cmd != nil && menu.argIndex < len(cmd.Args) {
-			arg = &cmd.Args[menu.argIndex]
-		}
-		switch ev := ev.(type) {
-		case *tcell.EventKey:
-			switch ev.Key() {
-			case tcell.KeyEscape:
-				if menu.enableScape {
-					menu.Show()
-					go menu.EventManager()
-					return
-				}
-			case tcell.KeyEnter:
-				if arg != nil && !arg.IsBoolean {
-					arg.Value = string(menu.runeBuffer)
-					menu.NextArgument()
-				}
-			case tcell.KeyCtrlL:
-				menu.p.Sync()
-			case tcell.KeyBackspace, tcell.KeyBackspace2:
-				if arg != nil && !arg.IsBoolean {
-					menu.runeBuffer = menu.runeBuffer[:len(menu.runeBuffer)-1]
-					menu.p.PutEcho(string(append(menu.runeBuffer, tcell.RuneBlock)), menu.p.style.Input)
-					menu.p.Show()
-				}
-			case tcell.KeyRune:
-				if arg != nil && arg.IsBoolean {
-					if ev.Rune() == 'y' || ev.Rune() == 'Y' {
-						arg.Valuebool = true
-						menu.NextArgument()
-						break
-					}
-					if ev.Rune() == 'N' || ev.Rune() == 'n' {
-						arg.Valuebool = false
-						menu.NextArgument()
-						break
-					}
-				} else {
-					menu.runeBuffer = append(menu.runeBuffer, ev.Rune())
-					menu.p.PutEcho(string(append(menu.runeBuffer, tcell.RuneBlock)), menu.p.style.Input)
-					menu.p.Show()
-				}
-			}
-
-		case *tcell.EventResize:
-			menu.p.Sync()
-		}
-	}
-}
-
-//Handles Key events for Menu
-func (menu *Menu) EventManager() {
-	for {
-		ev := menu.p.Screen().PollEvent()
-		switch ev := ev.(type) {
-		case *tcell.EventKey:
-			switch ev.Key() {
-			case tcell.KeyEscape:
-				close(menu.Wait)
-				return
-			case tcell.KeyEnter:
-				cmd := menu.CurrentCommand()
-				if cmd != nil && cmd.Disable {
-					continue
-				}
-				if menu.IsToggle() {
-					menu.SelectToggle()
-					continue
-				}
-				menu.argIndex = 0
-				menu.enableScape = true
-				menu.ShowCommand()
-				go menu.EventCommandManager()
-				return
-
-			case tcell.KeyCtrlL:
-				menu.p.Sync()
-			case tcell.KeyUp:
-				menu.Prev()
-				menu.p.Show()
-			case tcell.KeyDown:
-				menu.Next()
-				menu.p.Show()
-			}
-		case *tcell.EventResize:
-			menu.p.Sync()
-		}
-	}
+	}, nil
 }
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+)
+
+//Kind of an Event emitted by a handler
+type EventKind int
+
+const (
+	EventStdout   EventKind = iota //Text is output, the text sent to the handler channel
+	EventStderr                    //Text is error output, shown in red
+	EventProgress                  //Done of Total units of work, with an optional Text
+	EventStatus                    //Text is the new Status of the command
+	EventLog                       //Text is a log line of the given Level
+	EventResult                    //Key and Value are a result of the command
+)
+
+//Level of a log line
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+func (l LogLevel) String() string {
+	if l < 0 || int(l) >= len(logLevels) {
+		return "log"
+	}
+	return logLevels[l]
+}
+
+//escape sequences coloring the level of log lines in the output
+var logTags = map[LogLevel]string{
+	LogDebug: "\x1b[2mdebug\x1b[22m: ",
+	LogInfo:  "info: ",
+	LogWarn:  "\x1b[33mwarn\x1b[39m: ",
+	LogError: "\x1b[31merror\x1b[39m: ",
+}
+
+//Typed event emitted by a handler with Command.Emit, fields are used according to Kind
+type Event struct {
+	Kind    EventKind
+	Text    string
+	Level   LogLevel
+	Done    int64
+	Total   int64
+	Key     string
+	Value   string
+	percent bool    //progress is a percent of a Total of 100*100
+	prompt  *prompt //question for the user, not recorded
+}
+
+//Key/value result of a command, set by its handler
+type Result struct {
+	Key   string
+	Value string
+}
+
+//Emits a typed event from the handler of a running command, it is recorded in Events and shown
+//by the menu. Events must be emitted before the handler closes its channel
+func (c *Command) Emit(e Event) {
+	if c.events != nil {
+		c.events <- e
+	}
+}
+
+//Emits a log line
+func (c *Command) Log(level LogLevel, text string) {
+	c.Emit(Event{Kind: EventLog, Level: level, Text: text})
+}
+
+//Emits a new Status for the command, shown while it runs and in the menu
+func (c *Command) SetStatus(text string) {
+	c.Emit(Event{Kind: EventStatus, Text: text})
+}
+
+//Emits a result, setting a key that was already set replaces its value
+func (c *Command) SetResult(key, value string) {
+	c.Emit(Event{Kind: EventResult, Key: key, Value: value})
+}
+
+//Gets the value of a result set by the last run
+func (c *Command) Result(key string) (string, bool) {
+	for _, r := range c.Results {
+		if r.Key == key {
+			return r.Value, true
+		}
+	}
+	return "", false
+}
+
+//starts recording the events of a new run
+func (c *Command) resetEvents() {
+	c.Events = nil
+	c.Results = nil
+	c.events = make(chan Event)
+}
+
+//records an event in Events, updating the results and status
+func (c *Command) record(e Event) {
+	c.Events = append(c.Events, e)
+	switch e.Kind {
+	case EventStatus:
+		c.Status = e.Text
+	case EventResult:
+		for i := range c.Results {
+			if c.Results[i].Key == e.Key {
+				c.Results[i].Value = e.Value
+				return
+			}
+		}
+		c.Results = append(c.Results, Result{e.Key, e.Value})
+	}
+}
+
+//delivers the text sent to ch and the events emitted by a handler, in order, until ch is closed
+func forward(ch chan string, events chan Event, post func(Event)) {
+	for {
+		select {
+		case text, ok := <-ch:
+			if !ok {
+				return
+			}
+			post(Event{Kind: EventStdout, Text: text})
+		case e := <-events:
+			post(e)
+		}
+	}
+}
+
+//gets the output of the last run, error output in red and log lines tagged with their level
+func (c *Command) output() string {
+	var out bytes.Buffer
+	for _, e := range c.Events {
+		switch e.Kind {
+		case EventStdout:
+			out.WriteString(e.Text)
+		case EventStderr:
+			text := strings.TrimSuffix(e.Text, "\n")
+			out.WriteString("\x1b[31m" + text + "\x1b[39m" + e.Text[len(text):])
+		case EventLog:
+			if out.Len() > 0 && !strings.HasSuffix(out.String(), "\n") {
+				out.WriteString("\n")
+			}
+			out.WriteString(logTags[e.Level] + e.Text + "\n")
+		}
+	}
+	return out.String()
+}
+
+//gets the text written for an event by RunHeadless
+func (e Event) headlessText() string {
+	switch e.Kind {
+	case EventStdout, EventStderr:
+		return e.Text
+	case EventLog:
+		return e.Level.String() + ": " + e.Text + "\n"
+	}
+	return ""
+}
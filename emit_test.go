@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func emitAll(c *Command, ch chan string) {
+	defer close(ch)
+	ch <- "building\n"
+	c.Emit(Event{Kind: EventStderr, Text: "deprecated flag\n"})
+	c.SetStatus("linking")
+	c.Log(LogWarn, "cache miss")
+	c.SetResult("version", "1.0")
+	c.SetResult("version", "1.1")
+	c.SetResult("binary", "./app")
+}
+
+func TestCommandEvents(t *testing.T) {
+	c := Command{Title: "Build", Execute: emitAll, PrintOut: true}
+	m, _ := runCommand(t, &c)
+	defer m.Quit()
+	kinds := []EventKind{EventStdout, EventStderr, EventStatus, EventLog, EventResult, EventResult, EventResult}
+	if len(c.Events) != len(kinds) {
+		t.Fatalf("expected %d events, got %v", len(kinds), c.Events)
+	}
+	for i, k := range kinds {
+		if c.Events[i].Kind != k {
+			t.Errorf("event %d out of order: %v", i, c.Events[i])
+		}
+	}
+	if len(c.Results) != 2 {
+		t.Errorf("expected a result per key, got %v", c.Results)
+	}
+	if v, ok := c.Result("version"); !ok || v != "1.1" {
+		t.Errorf("result not replaced: %q", v)
+	}
+	if c.Status != "linking" {
+		t.Errorf("status not set: %q", c.Status)
+	}
+	lines, _ := resultLines(&c)
+	want := []string{"building", "\x1b[31mdeprecated flag\x1b[39m", "\x1b[33mwarn\x1b[39m: cache miss", "", "version: 1.1", "binary: ./app"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("unexpected result lines %q", lines)
+	}
+}
+
+func TestRunHeadlessEvents(t *testing.T) {
+	c := Command{Execute: emitAll}
+	var out, errOut bytes.Buffer
+	if err := RunHeadless(&c, &out, &errOut); err != nil {
+		t.Fatal(err)
+	}
+	if s := out.String(); s != "building\nwarn: cache miss\nbinary=./app\nversion=1.1\n" {
+		t.Errorf("unexpected output %q", s)
+	}
+	if s := errOut.String(); s != "deprecated flag\n" {
+		t.Errorf("unexpected error output %q", s)
+	}
+	if v, _ := c.Result("binary"); v != "./app" {
+		t.Errorf("result not recorded: %q", v)
+	}
+}
+
+func TestOSCmdHandlerStderr(t *testing.T) {
+	c := Command{Cli: "./sampleapp/testcommands/stderr.sh"}
+	var out, errOut bytes.Buffer
+	if err := RunHeadless(&c, &out, &errOut); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "Checking\n" || errOut.String() != "Disk almost full\n" {
+		t.Errorf("unexpected output %q and error output %q", out.String(), errOut.String())
+	}
+	stderr := false
+	for _, e := range c.Events {
+		stderr = stderr || e.Kind == EventStderr
+	}
+	if !stderr {
+		t.Errorf("no error output event in %v", c.Events)
+	}
+}
@@ -0,0 +1,384 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+//All the menu state and drawing is owned by a single event loop. Handler output,
+//completion and progress ticks are posted to the screen as events, so no other
+//goroutine ever touches the menu or the screen contents
+
+//what the menu is currently showing, decides how key events are handled
+type view int
+
+const (
+	viewMenu view = iota
+	viewArgument
+	viewRunning
+	viewResult
+	viewTerminal
+	viewPrompt
+)
+
+//posted with output or an event from the handler of a running command
+type eventOutput struct {
+	tcell.EventTime
+	c *Command
+	e Event
+}
+
+func newEventOutput(c *Command, e Event) *eventOutput {
+	ev := &eventOutput{c: c, e: e}
+	ev.SetEventNow()
+	return ev
+}
+
+//posted once the handler of a running command completes
+type eventDone struct {
+	tcell.EventTime
+	c *Command
+}
+
+func newEventDone(c *Command) *eventDone {
+	ev := &eventDone{c: c}
+	ev.SetEventNow()
+	return ev
+}
+
+//posted at regular intervals to animate progress
+type eventTick struct {
+	tcell.EventTime
+}
+
+//posts tick events to the screen until stop is closed
+func postTicks(s tcell.Screen, interval time.Duration, stop chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-t.C:
+			ev := &eventTick{}
+			ev.SetEventTime(now)
+			//ticks are dropped if the queue is full, the next one will do
+			s.PostEvent(ev)
+		}
+	}
+}
+
+//where Run prints the stack of a panic, redirected by tests
+var panicOutput io.Writer = os.Stderr
+
+//Shows the menu and handles its events until the user exits
+//The screen is always restored, also when a panic occurs, in that case the stack is printed
+//once the terminal is back to normal and the panic is returned as an error
+func (m *Menu) Run() (err error) {
+	defer func() {
+		r := recover()
+		m.Quit()
+		if r != nil {
+			fmt.Fprintf(panicOutput, "panic: %v\n\n%s", r, debug.Stack())
+			err = fmt.Errorf("tui: panic: %v", r)
+		}
+	}()
+	m.Show()
+	m.eventLoop()
+	return m.err
+}
+
+//Handles key events for commnands, then goes back to handling the menu
+//Kept for compatibility, EventManager handles every view
+func (menu *Menu) EventCommandManager() {
+	menu.EventManager()
+}
+
+//Handles events for Menu until the user exits, then closes Wait
+func (menu *Menu) EventManager() {
+	menu.eventLoop()
+	close(menu.Wait)
+}
+
+//polls events until the user exits the menu
+func (menu *Menu) eventLoop() {
+	if menu.Mouse {
+		menu.p.Screen().EnableMouse()
+	}
+	for {
+		ev := menu.p.Screen().PollEvent()
+		if ev == nil {
+			//screen has been finalised
+			return
+		}
+		if !menu.handleEvent(ev) || menu.err != nil {
+			return
+		}
+	}
+}
+
+//handles a single event, returns false when the user exits the menu
+func (menu *Menu) handleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *eventOutput:
+		menu.handlerEvent(ev.c, ev.e)
+		return true
+	case *eventTick:
+		if menu.view == viewRunning {
+			menu.progress.Tick()
+		}
+		return true
+	case *eventDone:
+		menu.commandDone(ev.c)
+		return true
+	case *tcell.EventResize:
+		menu.draw()
+		menu.p.Sync()
+		return true
+	}
+
+	if menu.view == viewTerminal {
+		menu.terminalEvent(ev)
+		return true
+	}
+	if menu.help {
+		menu.helpEvent(ev)
+		return true
+	}
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		if menu.Mouse {
+			return menu.mouseEvent(ev)
+		}
+		return true
+	case *tcell.EventKey:
+		switch a, _ := menu.action(ev); a {
+		case ActionRedraw:
+			menu.p.Sync()
+			return true
+		case ActionHelp:
+			menu.ShowHelp()
+			return true
+		}
+	}
+
+	switch menu.view {
+	case viewMenu:
+		return menu.menuEvent(ev)
+	case viewArgument:
+		menu.argumentEvent(ev)
+	case viewResult:
+		return menu.resultEvent(ev)
+	case viewPrompt:
+		menu.promptEvent(ev)
+	}
+	return true
+}
+
+//records an event from the handler of a command, showing progress and status while it runs
+func (menu *Menu) handlerEvent(c *Command, e Event) {
+	if e.prompt != nil {
+		menu.showPrompt(c, e.prompt)
+		return
+	}
+	c.record(e)
+	if menu.view != viewRunning || menu.command != c {
+		return
+	}
+	switch e.Kind {
+	case EventProgress:
+		menu.progress.Update(e.Done, e.Total, e.Text)
+		menu.progress.percent = e.percent
+		menu.drawRunning()
+	case EventStatus:
+		menu.progress.Text = e.Text
+		menu.drawRunning()
+	}
+}
+
+//gets the keymap of the menu
+func (menu *Menu) keymap() Keymap {
+	if menu.Keymap == nil {
+		menu.Keymap = DefaultKeymap()
+	}
+	return menu.Keymap
+}
+
+//finds the action for a key event, returns -1 if the key has none
+func (menu *Menu) action(ev *tcell.EventKey) (Action, bool) {
+	if a, ok := menu.keymap().action(ev, menu.typing()); ok {
+		return a, true
+	}
+	return -1, false
+}
+
+//draws the current view again, used when the screen is resized
+func (menu *Menu) draw() {
+	switch menu.view {
+	case viewMenu:
+		menu.Show()
+	case viewArgument:
+		menu.drawArgument()
+	case viewRunning:
+		menu.drawRunning()
+	case viewResult:
+		menu.ShowResult(menu.command)
+	case viewTerminal:
+		menu.drawTerminal()
+	case viewPrompt:
+		menu.drawPrompt()
+	}
+	if menu.help {
+		menu.drawHelp()
+	}
+}
+
+//handles an event while the result of a command is shown, returns false when the user exits
+func (menu *Menu) resultEvent(ev tcell.Event) bool {
+	ek, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return true
+	}
+	_, page := menu.p.Screen().Size()
+	page /= 2
+	switch a, _ := menu.action(ek); a {
+	case ActionBack:
+		menu.Show()
+	case ActionQuit:
+		return false
+	case ActionUp:
+		menu.scrollResult(-1)
+	case ActionDown:
+		menu.scrollResult(1)
+	case ActionPageUp:
+		menu.scrollResult(-page)
+	case ActionPageDown:
+		menu.scrollResult(page)
+	case ActionTop:
+		menu.scrollResult(-menu.resultScroll)
+	case ActionBottom:
+		//the scroll is limited to the last page when drawn
+		lines, _ := resultLines(menu.command)
+		menu.scrollResult(len(lines))
+	}
+	return true
+}
+
+//handles an event while an argument is requested
+func (menu *Menu) argumentEvent(ev tcell.Event) {
+	ek, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+	arg := &menu.command.Args[menu.argIndex]
+	if a, ok := menu.action(ek); ok {
+		switch a {
+		case ActionBack:
+			menu.Show()
+		case ActionSelect:
+			if !arg.IsBoolean {
+				arg.Value = string(menu.runeBuffer)
+				menu.NextArgument()
+			}
+		}
+		return
+	}
+	switch ek.Key() {
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if !arg.IsBoolean && len(menu.runeBuffer) > 0 {
+			menu.runeBuffer = menu.runeBuffer[:len(menu.runeBuffer)-1]
+			menu.p.PutEcho(string(append(menu.runeBuffer, tcell.RuneBlock)), menu.p.style.Input)
+			menu.p.Show()
+		}
+	case tcell.KeyRune:
+		if arg.IsBoolean {
+			switch ek.Rune() {
+			case 'y', 'Y':
+				arg.Valuebool = true
+				menu.NextArgument()
+			case 'n', 'N':
+				arg.Valuebool = false
+				menu.NextArgument()
+			}
+		} else {
+			menu.runeBuffer = append(menu.runeBuffer, ek.Rune())
+			menu.p.PutEcho(string(append(menu.runeBuffer, tcell.RuneBlock)), menu.p.style.Input)
+			menu.p.Show()
+		}
+	}
+}
+
+//handles an event while the menu is shown, returns false when the user exits
+func (menu *Menu) menuEvent(ev tcell.Event) bool {
+	ek, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return true
+	}
+	_, page := menu.p.Screen().Size()
+	page /= 2
+	if a, ok := menu.action(ek); ok {
+		switch a {
+		case ActionBack:
+			if !menu.filtering {
+				return false
+			}
+			menu.clearFilter()
+		case ActionQuit:
+			return false
+		case ActionSelect:
+			menu.activate()
+		case ActionToggle:
+			if menu.IsToggle() {
+				menu.SelectToggle()
+				menu.Show()
+			}
+		case ActionUp:
+			menu.Prev()
+		case ActionDown:
+			menu.Next()
+		case ActionTop:
+			menu.moveCursor(-len(menu.Commands), false)
+			menu.Show()
+		case ActionBottom:
+			menu.moveCursor(len(menu.Commands), false)
+			menu.Show()
+		case ActionPageUp:
+			menu.moveCursor(-page, false)
+			menu.Show()
+		case ActionPageDown:
+			menu.moveCursor(page, false)
+			menu.Show()
+		}
+		return true
+	}
+	switch ek.Key() {
+	case tcell.KeyRune:
+		menu.typeRune(ek.Rune())
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(menu.filter) > 0 {
+			menu.setFilter(menu.filter[:len(menu.filter)-1])
+		}
+	}
+	return true
+}
+
+//runs or toggles the command under the cursor
+func (menu *Menu) activate() {
+	cmd := menu.CurrentCommand()
+	if cmd == nil || cmd.Disable || menu.cursorPos(menu.visible()) < 0 {
+		return
+	}
+	if menu.IsToggle() {
+		menu.SelectToggle()
+		menu.Show()
+		return
+	}
+	menu.argIndex = 0
+	menu.runeBuffer = []rune{}
+	menu.ShowCommand()
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"sort"
+	"unicode"
+)
+
+//Type to filter: "/" or typing a letter that is not a hotkey opens a filter line in the menu,
+//which narrows the commands shown to those matching it on title or description
+
+//a command shown in the menu list, with the runes of its title matched by the filter
+type listItem struct {
+	index  int
+	marked map[int]bool
+	score  int
+}
+
+//matches a pattern as a subsequence of s ignoring case
+//returns the indexes of the runes matched and a score, higher for matches at the start
+//of words, for consecutive runes and for matches closer to the start of s
+func fuzzyMatch(pattern []rune, s string) (map[int]bool, int, bool) {
+	marked := map[int]bool{}
+	if len(pattern) == 0 {
+		return marked, 0, true
+	}
+	rs := []rune(s)
+	score := 0
+	p := 0
+	for i := 0; i < len(rs) && p < len(pattern); i++ {
+		if unicode.ToLower(rs[i]) != unicode.ToLower(pattern[p]) {
+			continue
+		}
+		score++
+		if marked[i-1] {
+			score += 2
+		}
+		if i == 0 || !(unicode.IsLetter(rs[i-1]) || unicode.IsDigit(rs[i-1])) {
+			score += 3
+		}
+		if p == 0 && i > 3 {
+			score -= 3
+		} else if p == 0 {
+			score -= i
+		}
+		marked[i] = true
+		p++
+	}
+	if p < len(pattern) {
+		return nil, 0, false
+	}
+	return marked, score, true
+}
+
+//gets the commands shown in the menu, all of them or those matching the filter, best first
+func (m *Menu) visible() []listItem {
+	items := []listItem{}
+	for i, c := range m.Commands {
+		if !m.filtering {
+			items = append(items, listItem{index: i})
+			continue
+		}
+		if marked, score, ok := fuzzyMatch(m.filter, c.Title); ok {
+			items = append(items, listItem{index: i, marked: marked, score: score * 2})
+		} else if _, score, ok := fuzzyMatch(m.filter, c.Description); ok {
+			items = append(items, listItem{index: i, score: score})
+		}
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].score > items[b].score
+	})
+	return items
+}
+
+//gets the position of the cursor in a list of items, -1 when the command is not shown
+func (m *Menu) cursorPos(items []listItem) int {
+	for pos, it := range items {
+		if it.index == m.Cursor {
+			return pos
+		}
+	}
+	return -1
+}
+
+//sets the filter and moves the cursor to the best match
+func (m *Menu) setFilter(filter []rune) {
+	m.filtering = true
+	m.filter = filter
+	m.scroll = 0
+	if items := m.visible(); len(items) > 0 {
+		m.Cursor = items[0].index
+	}
+	m.Show()
+}
+
+//closes the filter line showing all the commands
+func (m *Menu) clearFilter() {
+	m.filtering = false
+	m.filter = nil
+	m.Show()
+}
+
+//handles a key typed in the menu
+func (m *Menu) typeRune(r rune) {
+	switch {
+	case m.filtering:
+		m.setFilter(append(m.filter, r))
+	case r == '/':
+		m.setFilter(nil)
+	default:
+		if i := m.quickSelect(r); i >= 0 {
+			m.Cursor = i
+			m.Show()
+			m.activate()
+		} else if unicode.IsLetter(r) {
+			m.setFilter([]rune{r})
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	marked, score, ok := fuzzyMatch([]rune("dpl"), "Deploy")
+	if !ok || !marked[0] || !marked[2] || !marked[3] || len(marked) != 3 {
+		t.Errorf("unexpected match %v", marked)
+	}
+	_, other, _ := fuzzyMatch([]rune("dpl"), "Update plan")
+	if other >= score {
+		t.Errorf("word start should score higher, %d >= %d", other, score)
+	}
+	if _, _, ok := fuzzyMatch([]rune("xyz"), "Deploy"); ok {
+		t.Error("unexpected match")
+	}
+}
+
+func TestFilterCommands(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Quit()
+	m.Commands = []Command{
+		{Title: "Build", Optional: true},
+		{Title: "Update plan", Optional: true},
+		{Title: "Deploy", Optional: true},
+		{Title: "Logs", Description: "show deployment logs", Optional: true},
+	}
+	m.Show()
+	key := func(k tcell.Key, r rune) {
+		m.handleEvent(tcell.NewEventKey(k, r, tcell.ModNone))
+	}
+	for _, r := range "/dpl" {
+		key(tcell.KeyRune, r)
+	}
+	items := m.visible()
+	if len(items) != 3 || items[0].index != 2 || items[2].index != 3 {
+		t.Fatalf("unexpected matches %v", items)
+	}
+	if screenLine(s, 0) != "  /dpl"+string(tcell.RuneBlock) {
+		t.Errorf("filter line not shown: %q", screenLine(s, 0))
+	}
+	if l := screenLine(s, 2); l != "  [ ] Deploy" {
+		t.Errorf("top hit not shown first: %q", l)
+	}
+
+	key(tcell.KeyEnter, 0)
+	if !m.Commands[2].Selected {
+		t.Error("enter did not activate the top hit")
+	}
+	key(tcell.KeyRune, 'x')
+	if len(m.visible()) != 0 {
+		t.Error("expected no matches")
+	}
+	key(tcell.KeyEnter, 0)
+	if m.Commands[2].Selected != true {
+		t.Error("enter with no matches changed a command")
+	}
+	key(tcell.KeyBackspace2, 0)
+	if len(m.visible()) != 3 {
+		t.Error("backspace did not widen the filter")
+	}
+	key(tcell.KeyEscape, 0)
+	if m.filtering || len(m.visible()) != 4 {
+		t.Error("escape did not clear the filter")
+	}
+
+	//typing a letter also opens the filter
+	key(tcell.KeyRune, 'l')
+	if !m.filtering || m.Cursor != 3 {
+		t.Errorf("typing did not filter, cursor %d", m.Cursor)
+	}
+}
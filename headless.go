@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//Argument values used to run a command without a terminal
+//Values are keyed by the argument Name, or by its Title when Name is empty
+//Boolean arguments accept any value understood by strconv.ParseBool
+type ArgValues map[string]string
+
+//Reads argument values from a file with a JSON object, e.g. {"second": "foo", "first": true}
+//Values are strings, numbers or booleans, numbers are kept as written
+func ReadArgValues(path string) (ArgValues, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	raw := map[string]interface{}{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	v := ArgValues{}
+	for k, i := range raw {
+		switch i := i.(type) {
+		case string:
+			v[k] = i
+		case json.Number:
+			v[k] = i.String()
+		case bool:
+			v[k] = strconv.FormatBool(i)
+		case nil:
+			return nil, fmt.Errorf("argument %q: null is not a value", k)
+		default:
+			return nil, fmt.Errorf("argument %q: objects and arrays are not values", k)
+		}
+	}
+	return v, nil
+}
+
+//Adds the values found in environment variables for the arguments of a command
+//The variable name is the prefix plus the argument key in upper case, e.g. TUI_ARG_SECOND
+func (v ArgValues) FromEnv(c *Command, prefix string) {
+	for _, a := range c.Args {
+		if val, ok := os.LookupEnv(prefix + envName(a.key())); ok {
+			v[a.key()] = val
+		}
+	}
+}
+
+//gets the key used to address an argument
+func (a *Argument) key() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Title
+}
+
+//converts a key into a valid environment variable name
+func envName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return '_'
+		}
+		return unicode.ToUpper(r)
+	}, key)
+}
+
+//Sets the arguments of a command from values, values for arguments it does not have are an error
+//Arguments without a value keep the Value or Valuebool of their definition, as in the menu
+//Arguments that are not booleans must have a value when their definition has none
+func (c *Command) SetArgs(v ArgValues) error {
+	known := map[string]bool{}
+	for _, a := range c.Args {
+		known[a.key()] = true
+	}
+	unknown := []string{}
+	for k := range v {
+		if !known[k] {
+			unknown = append(unknown, strconv.Quote(k))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown arguments %s", strings.Join(unknown, ", "))
+	}
+	for i := range c.Args {
+		a := &c.Args[i]
+		val, ok := v[a.key()]
+		if a.IsBoolean {
+			if !ok {
+				continue
+			}
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("argument %q: %v", a.key(), err)
+			}
+			a.Valuebool = b
+			continue
+		}
+		if !ok {
+			if a.Value == "" {
+				return fmt.Errorf("missing value for argument %q", a.key())
+			}
+			continue
+		}
+		a.Value = val
+	}
+	return nil
+}
+
+//Finds a command by its breadcrumb path, e.g. "Test > No Args"
+//The menu title can be left out of the path
+func (m *Menu) FindCommand(path string) *Command {
+	parts := strings.Split(path, ">")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	path = strings.Join(parts, " > ")
+	for i := range m.Commands {
+		c := &m.Commands[i]
+		c.breadCrum = m.BreadCrum()
+		if path == c.BreadCrum() || path == c.Title {
+			return c
+		}
+	}
+	return nil
+}
+
+//Runs a command without a terminal, streaming its output to out and its error output to errOut
+//Questions asked by the command are written to out and answered from the standard input
+//Results set by the command are written to out once it completes, as key=value lines sorted by key
+//Returns the command Error once the handler completes
+func RunHeadless(c *Command, out, errOut io.Writer) error {
+	if c.Execute == nil {
+		c.Execute = OSCmdHandler
+	}
+	c.resetEvents()
+	in := bufio.NewReader(os.Stdin)
+	ch := make(chan string)
+	go execute(c, ch)
+	forward(ch, c.events, func(e Event) {
+		if e.prompt != nil {
+			show := func() {}
+			if e.prompt.kind == promptSecret {
+				show = hideInput(os.Stdin)
+			}
+			r := e.prompt.answerFrom(in, out)
+			show()
+			e.prompt.reply <- r
+			return
+		}
+		c.record(e)
+		w := out
+		if e.Kind == EventStderr {
+			w = errOut
+		}
+		io.WriteString(w, e.headlessText())
+	})
+	writeResults(out, c.Results)
+	return c.Error
+}
+
+//writes results as key=value lines sorted by key
+func writeResults(w io.Writer, results []Result) {
+	lines := make([]string, 0, len(results))
+	for _, r := range results {
+		lines = append(lines, r.Key+"="+r.Value+"\n")
+	}
+	sort.Strings(lines)
+	io.WriteString(w, strings.Join(lines, ""))
+}
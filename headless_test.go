@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	m := &Menu{Title: "Test", Commands: []Command{{Title: "One"}, {Title: "Two"}}}
+	for _, path := range []string{"Two", "Test > Two", "Test>Two"} {
+		c := m.FindCommand(path)
+		if c == nil || c.Title != "Two" {
+			t.Errorf("path %q not found", path)
+		}
+	}
+	if m.FindCommand("Other > Two") != nil {
+		t.Error("found command on wrong menu")
+	}
+}
+
+func TestSetArgs(t *testing.T) {
+	c := Command{Args: []Argument{
+		{Name: "first", IsBoolean: true},
+		{Name: "second"},
+		{Title: "Third Value"},
+	}}
+	os.Setenv("TUITEST_THIRD_VALUE", "env")
+	defer os.Unsetenv("TUITEST_THIRD_VALUE")
+	v := ArgValues{"first": "true", "second": "foo"}
+	v.FromEnv(&c, "TUITEST_")
+	if err := c.SetArgs(v); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Args[0].Valuebool || c.Args[1].Value != "foo" || c.Args[2].Value != "env" {
+		t.Errorf("unexpected values %+v", c.Args)
+	}
+	missing := Command{Args: []Argument{{Name: "second"}}}
+	if err := missing.SetArgs(ArgValues{}); err == nil {
+		t.Error("expected error for missing value")
+	}
+	if err := c.SetArgs(ArgValues{"first": "maybe", "second": "", "Third Value": ""}); err == nil {
+		t.Error("expected error for invalid boolean")
+	}
+	err := c.SetArgs(ArgValues{"frist": "true", "second": "foo", "Third Value": "", "zero": ""})
+	if err == nil || !strings.Contains(err.Error(), `"frist", "zero"`) {
+		t.Errorf("expected error naming the unknown arguments, got %v", err)
+	}
+}
+
+func TestSetArgsDefaults(t *testing.T) {
+	c := Command{Args: []Argument{
+		{Name: "first", IsBoolean: true, Valuebool: true},
+		{Name: "second", Value: "foo"},
+		{Name: "third", IsBoolean: true, Valuebool: true},
+	}}
+	if err := c.SetArgs(ArgValues{"third": "false"}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Args[0].Valuebool || c.Args[1].Value != "foo" || c.Args[2].Valuebool {
+		t.Errorf("defaults not kept %+v", c.Args)
+	}
+}
+
+func TestReadArgValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "args.json")
+	write := func(json string) {
+		if err := ioutil.WriteFile(path, []byte(json), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"replicas": 10000000, "ratio": 0.5, "name": "web", "first": true}`)
+	v, err := ReadArgValues(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ArgValues{"replicas": "10000000", "ratio": "0.5", "name": "web", "first": "true"}
+	for k, val := range want {
+		if v[k] != val {
+			t.Errorf("%s: expected %q, got %q", k, val, v[k])
+		}
+	}
+	for _, json := range []string{`{"a": null}`, `{"a": {}}`, `{"a": [1]}`, `[]`} {
+		write(json)
+		if _, err := ReadArgValues(path); err == nil {
+			t.Errorf("expected error for %s", json)
+		}
+	}
+}
+
+func TestRunHeadless(t *testing.T) {
+	c := Command{
+		Cli:  "echo",
+		Args: []Argument{{Name: "hello", IsBoolean: true, Valuebool: true}},
+	}
+	out := &bytes.Buffer{}
+	if err := RunHeadless(&c, out, out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "hello\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+	c = Command{Cli: "./sampleapp/testcommands/args.sh"}
+	if err := RunHeadless(&c, &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Error("expected command to fail")
+	}
+}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+//Help overlay, opened with F1 or "?" when not typing, lists the keys that work on the
+//current screen and the description of the command selected. Any key closes it
+
+//a key and what it does
+type binding struct {
+	keys   string
+	action string
+}
+
+//gets the keys that work on the current view
+func (m *Menu) bindings() []binding {
+	b := []binding{}
+	k := m.keymap()
+	add := func(keys, action string) {
+		if keys != "" {
+			b = append(b, binding{keys, action})
+		}
+	}
+	switch m.view {
+	case viewMenu:
+		add(k.names(ActionUp, ActionDown), "move between commands")
+		add(k.names(ActionPageUp, ActionPageDown), "move a page of commands")
+		add(k.names(ActionTop, ActionBottom), "go to the first or last command")
+		add(k.names(ActionSelect), "run or toggle the selected command")
+		add(k.names(ActionToggle), "toggle the selected command")
+		add("1-9", "run a command by its position")
+		add("underlined key", "run the command with that hotkey")
+		add("/ or typing", "filter commands")
+		add("Backspace", "delete from the filter")
+		if m.filtering {
+			add(k.names(ActionBack), "clear the filter")
+		} else {
+			add(k.names(ActionBack), "exit")
+		}
+		add(k.names(ActionQuit), "exit")
+		if m.Mouse {
+			add("click", "select a command")
+			add("double click", "run a command")
+			add("wheel", "move between commands")
+		}
+	case viewArgument:
+		if m.command.Args[m.argIndex].IsBoolean {
+			add("Y, N", "answer yes or no")
+		} else {
+			add("typing", "enter the value")
+			add("Backspace", "delete the last character")
+			add(k.names(ActionSelect), "accept the value")
+		}
+		add(k.names(ActionBack), "cancel and go back to the menu")
+	case viewPrompt:
+		switch m.prompt.kind {
+		case promptConfirm:
+			add("Y, N", "answer yes or no")
+		case promptChoice:
+			add(k.names(ActionUp, ActionDown), "move between answers")
+			add("1-9", "choose an answer by its position")
+			add(k.names(ActionSelect), "accept the answer")
+		default:
+			add("typing", "enter the answer")
+			add("Backspace", "delete the last character")
+			add(k.names(ActionSelect), "accept the answer")
+		}
+		add(k.names(ActionBack), "cancel the question")
+	case viewResult:
+		add(k.names(ActionUp, ActionDown), "scroll the output")
+		add(k.names(ActionPageUp, ActionPageDown), "scroll the output a page")
+		add(k.names(ActionTop, ActionBottom), "go to the start or end of the output")
+		add(k.names(ActionBack), "go back to the menu")
+		add(k.names(ActionQuit), "exit")
+		if m.Mouse {
+			add("wheel", "scroll the output")
+		}
+	}
+	add(k.names(ActionRedraw), "redraw the screen")
+	add(k.names(ActionHelp), "show this help")
+	return b
+}
+
+//reports whether keys typed are taken as text, so "?" does not open the help
+func (m *Menu) typing() bool {
+	switch m.view {
+	case viewMenu:
+		return m.filtering
+	case viewArgument:
+		return !m.command.Args[m.argIndex].IsBoolean
+	case viewPrompt:
+		return m.prompt.kind == promptText || m.prompt.kind == promptSecret
+	}
+	return false
+}
+
+//opens the help overlay on top of the current view
+func (m *Menu) ShowHelp() {
+	if m.view == viewRunning {
+		return
+	}
+	m.help = true
+	m.drawHelp()
+}
+
+//draws the help overlay in a box in the middle of the screen
+func (m *Menu) drawHelp() {
+	w, h := m.p.Screen().Size()
+	width := w - 8
+	if width > 70 {
+		width = 70
+	}
+	if width < 20 || h < 5 {
+		return
+	}
+	inner := width - 4
+
+	lines := []string{}
+	c := m.command
+	if m.view == viewMenu {
+		c = m.CurrentCommand()
+	}
+	if c != nil {
+		lines = append(lines, c.Title)
+		lines = append(lines, wrapText(c.Description, inner)...)
+		lines = append(lines, "")
+	}
+	//long key names are wrapped so the actions always have half of the box
+	keys := 0
+	for _, b := range m.bindings() {
+		keys = max(keys, runewidth.StringWidth(b.keys))
+	}
+	keys = min(keys, inner/2)
+	for _, b := range m.bindings() {
+		names := wrapText(b.keys, keys)
+		actions := wrapText(b.action, max(inner-keys-2, 1))
+		for i := 0; i < len(names) || i < len(actions); i++ {
+			l := ""
+			if i < len(names) {
+				l = names[i]
+			}
+			if i < len(actions) {
+				l += strings.Repeat(" ", keys-runewidth.StringWidth(l)+2) + actions[i]
+			}
+			lines = append(lines, l)
+		}
+	}
+	lines = append(lines, "", "Press any key to close")
+	if len(lines) > h-4 {
+		lines = append(lines[:h-5], "...")
+	}
+
+	style := m.p.style.Menu
+	x0 := (w - width) / 2
+	y0 := (h - len(lines) - 2) / 2
+	y1 := y0 + len(lines) + 1
+	for y := y0; y <= y1; y++ {
+		for x := x0; x < x0+width; x++ {
+			r := ' '
+			switch {
+			case (y == y0 || y == y1) && (x == x0 || x == x0+width-1):
+				r = cornerRune(y == y0, x == x0)
+			case y == y0 || y == y1:
+				r = tcell.RuneHLine
+			case x == x0 || x == x0+width-1:
+				r = tcell.RuneVLine
+			}
+			m.p.s.SetContent(x, y, r, nil, style)
+		}
+	}
+	m.p.putc(style, x0+2, y0, " Help ")
+	for i, l := range lines {
+		m.p.putc(style, x0+2, y0+1+i, l)
+	}
+	m.p.Show()
+}
+
+//gets the box corner for the top or bottom, left or right
+func cornerRune(top, left bool) rune {
+	switch {
+	case top && left:
+		return tcell.RuneULCorner
+	case top:
+		return tcell.RuneURCorner
+	case left:
+		return tcell.RuneLLCorner
+	}
+	return tcell.RuneLRCorner
+}
+
+//handles an event while the help is shown, any key or click closes it
+func (m *Menu) helpEvent(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+	case *tcell.EventMouse:
+		if ev.Buttons()&tcell.Button1 == 0 {
+			return
+		}
+	default:
+		return
+	}
+	m.help = false
+	m.draw()
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//Accelerator key for a command, pressing it in the menu runs the command
+//It is matched ignoring case and shown underlined in the command title
+//In menu files it is written as a one character string, e.g. "hotkey": "d"
+type Hotkey rune
+
+func (h *Hotkey) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return fmt.Errorf("hotkey must be a single character, got %q", s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	*h = Hotkey(r)
+	return nil
+}
+
+//reports whether a key pressed matches the hotkey
+func (h Hotkey) matches(r rune) bool {
+	return h != 0 && unicode.ToLower(rune(h)) == unicode.ToLower(r)
+}
+
+//gets the title to show for a command and the index of its hotkey in it, or -1
+//when the hotkey is not part of the title it is added at the end
+func (c *Command) hotkeyTitle() (string, int) {
+	if c.Hotkey == 0 {
+		return c.Title, -1
+	}
+	for i, r := range []rune(c.Title) {
+		if c.Hotkey.matches(r) {
+			return c.Title, i
+		}
+	}
+	title := c.Title + " (" + string(rune(c.Hotkey)) + ")"
+	return title, len([]rune(title)) - 2
+}
+
+//gets the index of the command selected by a key, hotkeys first and then digits 1-9 by position
+//returns -1 if the key selects no command
+func (m *Menu) quickSelect(r rune) int {
+	for i := range m.Commands {
+		if m.Commands[i].Hotkey.matches(r) {
+			return i
+		}
+	}
+	if i := strings.IndexRune("123456789", r); i >= 0 && i < len(m.Commands) {
+		return i
+	}
+	return -1
+}
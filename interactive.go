@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+//Terminal type interactive commands run with, the escape sequences they use are emulated
+const TerminalType = "xterm-256color"
+
+//posted with output from an interactive command
+type eventTerminal struct {
+	tcell.EventTime
+	data []byte
+}
+
+func newEventTerminal(data []byte) *eventTerminal {
+	ev := &eventTerminal{data: data}
+	ev.SetEventNow()
+	return ev
+}
+
+//runs an Interactive command in a pseudo terminal drawn in place of the menu
+//all keys go to the command until it exits
+func (m *Menu) runInteractive(c *Command) {
+	c.resetEvents()
+	c.Error = nil
+	m.resultScroll = 0
+	m.command = c
+	w, h := m.terminalSize()
+
+	c.setEnv()
+	cliArray := strings.Split(c.Cli, " ")
+	cmd := exec.Command(cliArray[0], c.cliArgs()...)
+	cmd.Env = append(os.Environ(), "TERM="+TerminalType)
+	pty, err := startPty(cmd, w, h)
+	if err != nil {
+		c.Error = err
+		c.ran = true
+		m.ShowResult(c)
+		return
+	}
+	m.view = viewTerminal
+	m.pty = pty
+	m.term = newTerminal(w, h, m.p.style.ansiParser())
+	atomic.StoreInt32(&m.busy, 1)
+	m.drawTerminal()
+
+	s := m.p.Screen()
+	go func() {
+		b := make([]byte, 4096)
+		for {
+			n, err := pty.Read(b)
+			if n > 0 {
+				s.PostEventWait(newEventTerminal(append([]byte{}, b[:n]...)))
+			}
+			if err != nil {
+				break
+			}
+		}
+		c.Error = cmd.Wait()
+		s.PostEventWait(newEventDone(c))
+	}()
+}
+
+//called from the event loop when an interactive command exits, failures show their error
+func (m *Menu) terminalDone(c *Command) {
+	m.pty.Close()
+	m.pty = nil
+	m.term = nil
+	m.p.Screen().HideCursor()
+	if c.Error != nil {
+		m.ShowResult(c)
+		return
+	}
+	m.Show()
+}
+
+//gets the size of the terminal, between the title and the bottom bar
+func (m *Menu) terminalSize() (int, int) {
+	w, _ := m.p.Screen().Size()
+	return w, m.lastRow() - 1
+}
+
+//draws the terminal of the running interactive command, resizing it to the screen
+func (m *Menu) drawTerminal() {
+	if w, h := m.terminalSize(); w != m.term.w || h != m.term.h {
+		m.term.Resize(w, h)
+		setPtySize(m.pty, w, h)
+	}
+	s := m.p.Screen()
+	m.p.Clear()
+	m.p.Truncate = true
+	m.p.Putln(m.command.BreadCrum(), true)
+	m.p.Truncate = false
+	for y := 0; y < m.term.h; y++ {
+		for x := 0; x < m.term.w; x++ {
+			if r, style := m.term.Cell(x, y); r != 0 {
+				s.SetContent(x, y+1, r, nil, style)
+			}
+		}
+	}
+	if x, y, ok := m.term.Cursor(); ok {
+		s.ShowCursor(x, y+1)
+	} else {
+		s.HideCursor()
+	}
+	if m.BottomBar {
+		m.p.BottomBar(m.TerminalText)
+	}
+	m.buttons = nil
+	m.p.Show()
+}
+
+//handles an event while an interactive command runs, keys are sent to it
+func (m *Menu) terminalEvent(ev tcell.Event) {
+	switch ev := ev.(type) {
+	case *eventTerminal:
+		m.term.Write(ev.data)
+		if r := m.term.Replies(); len(r) > 0 {
+			m.pty.Write(r)
+		}
+		m.drawTerminal()
+	case *tcell.EventKey:
+		m.pty.Write(keyBytes(ev, m.term.appCursor))
+	}
+}
+
+//escape sequences sent by xterm for special keys
+var keySequences = map[tcell.Key]string{
+	tcell.KeyUp:      "\x1b[A",
+	tcell.KeyDown:    "\x1b[B",
+	tcell.KeyRight:   "\x1b[C",
+	tcell.KeyLeft:    "\x1b[D",
+	tcell.KeyHome:    "\x1b[H",
+	tcell.KeyEnd:     "\x1b[F",
+	tcell.KeyInsert:  "\x1b[2~",
+	tcell.KeyDelete:  "\x1b[3~",
+	tcell.KeyPgUp:    "\x1b[5~",
+	tcell.KeyPgDn:    "\x1b[6~",
+	tcell.KeyBacktab: "\x1b[Z",
+	tcell.KeyF1:      "\x1bOP",
+	tcell.KeyF2:      "\x1bOQ",
+	tcell.KeyF3:      "\x1bOR",
+	tcell.KeyF4:      "\x1bOS",
+	tcell.KeyF5:      "\x1b[15~",
+	tcell.KeyF6:      "\x1b[17~",
+	tcell.KeyF7:      "\x1b[18~",
+	tcell.KeyF8:      "\x1b[19~",
+	tcell.KeyF9:      "\x1b[20~",
+	tcell.KeyF10:     "\x1b[21~",
+	tcell.KeyF11:     "\x1b[23~",
+	tcell.KeyF12:     "\x1b[24~",
+}
+
+//gets the bytes a terminal sends for a key, cursor keys differ in application mode
+func keyBytes(ev *tcell.EventKey, appCursor bool) []byte {
+	b := []byte{}
+	if ev.Modifiers()&tcell.ModAlt != 0 {
+		b = append(b, 0x1b)
+	}
+	switch k := ev.Key(); {
+	case k == tcell.KeyRune:
+		buf := make([]byte, utf8.UTFMax)
+		return append(b, buf[:utf8.EncodeRune(buf, ev.Rune())]...)
+	case k < tcell.KeyRune:
+		return append(b, byte(k))
+	case appCursor && k >= tcell.KeyUp && k <= tcell.KeyLeft:
+		return append(b, "\x1bO"+keySequences[k][2:]...)
+	default:
+		return append(b, keySequences[k]...)
+	}
+}
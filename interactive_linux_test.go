@@ -0,0 +1,56 @@
+// +build linux
+
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestInteractive(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Quit()
+	m.Title = "Test"
+	m.Commands = []Command{{Title: "Prompt", Cli: "./sampleapp/testcommands/prompt.sh", Interactive: true}}
+	m.Show()
+	//interrupts wake the loop up to check the deadline
+	deadline := time.Now().Add(5 * time.Second)
+	stop := make(chan struct{})
+	defer close(stop)
+	go postTicks(s, 50*time.Millisecond, stop)
+	pump := func(done func() bool) {
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out, screen shows %q", screenLine(s, 1))
+			}
+			m.handleEvent(s.PollEvent())
+		}
+	}
+
+	m.activate()
+	pump(func() bool { return strings.Contains(screenLine(s, 1), "name?") })
+	for _, r := range "Ann" {
+		m.handleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	m.handleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	pump(func() bool { return screenLine(s, 2) == "Hello Ann" })
+	if l := screenLine(s, 1); l != "What is your name? Ann" {
+		t.Errorf("input not echoed: %q", l)
+	}
+	pump(func() bool { return !m.Busy() })
+
+	c := &m.Commands[0]
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	if m.view != viewMenu || m.pty != nil {
+		t.Error("menu not shown after the command exits")
+	}
+}
@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+//Something the user can do with a key, the keys for each action are set in a Keymap
+type Action int
+
+const (
+	ActionUp       Action = iota //move up a command, or scroll output up
+	ActionDown                   //move down a command, or scroll output down
+	ActionTop                    //move to the first command
+	ActionBottom                 //move to the last command
+	ActionPageUp                 //scroll output up a page
+	ActionPageDown               //scroll output down a page
+	ActionSelect                 //run the selected command, or accept a value
+	ActionToggle                 //toggle an optional command
+	ActionBack                   //go back to the menu, clear the filter or exit from the menu
+	ActionQuit                   //exit from the menu or result screens
+	ActionRedraw                 //redraw the screen
+	ActionHelp                   //open the help overlay
+	actionCount
+)
+
+//A key press, special keys set Key and characters set Rune
+type Key struct {
+	Key  tcell.Key
+	Rune rune
+}
+
+//Key for a special key, such as tcell.KeyUp
+func SpecialKey(k tcell.Key) Key {
+	return Key{Key: k}
+}
+
+//Key for a character
+func RuneKey(r rune) Key {
+	return Key{Key: tcell.KeyRune, Rune: r}
+}
+
+//gets the name of a key for the help
+func (k Key) name() string {
+	if k.Key == tcell.KeyRune && k.Rune == ' ' {
+		return "Space"
+	}
+	if k.Key == tcell.KeyRune {
+		return string(k.Rune)
+	}
+	if n, ok := tcell.KeyNames[k.Key]; ok {
+		return n
+	}
+	return "?"
+}
+
+//Maps actions to the keys that trigger them
+//Characters are not matched while the user types text, such as a value or the menu filter
+type Keymap map[Action][]Key
+
+//returns the default keymap, using arrows, Enter and ESC
+func DefaultKeymap() Keymap {
+	return Keymap{
+		ActionUp:       {SpecialKey(tcell.KeyUp)},
+		ActionDown:     {SpecialKey(tcell.KeyDown)},
+		ActionTop:      {SpecialKey(tcell.KeyHome)},
+		ActionBottom:   {SpecialKey(tcell.KeyEnd)},
+		ActionPageUp:   {SpecialKey(tcell.KeyPgUp)},
+		ActionPageDown: {SpecialKey(tcell.KeyPgDn)},
+		ActionSelect:   {SpecialKey(tcell.KeyEnter)},
+		ActionToggle:   {RuneKey(' ')},
+		ActionBack:     {SpecialKey(tcell.KeyEscape)},
+		ActionRedraw:   {SpecialKey(tcell.KeyCtrlL)},
+		ActionHelp:     {SpecialKey(tcell.KeyF1), RuneKey('?')},
+	}
+}
+
+//returns the default keymap plus vi style keys: j and k to move, g and G to go to the
+//first and last command, Ctrl-B and Ctrl-F to page and q to quit
+func ViKeymap() Keymap {
+	k := DefaultKeymap()
+	k.Add(ActionDown, RuneKey('j'))
+	k.Add(ActionUp, RuneKey('k'))
+	k.Add(ActionTop, RuneKey('g'))
+	k.Add(ActionBottom, RuneKey('G'))
+	k.Add(ActionPageUp, SpecialKey(tcell.KeyCtrlB))
+	k.Add(ActionPageDown, SpecialKey(tcell.KeyCtrlF))
+	k.Add(ActionQuit, RuneKey('q'))
+	return k
+}
+
+//Adds keys to an action
+func (k Keymap) Add(a Action, keys ...Key) {
+	k[a] = append(k[a], keys...)
+}
+
+//finds the action for a key event, characters are skipped if the user is typing text
+func (k Keymap) action(ev *tcell.EventKey, typing bool) (Action, bool) {
+	for a := Action(0); a < actionCount; a++ {
+		for _, key := range k[a] {
+			if key.Key != ev.Key() {
+				continue
+			}
+			if key.Key != tcell.KeyRune {
+				return a, true
+			}
+			if !typing && key.Rune == ev.Rune() {
+				return a, true
+			}
+		}
+	}
+	return 0, false
+}
+
+//gets the names of the keys for some actions, as shown in the help
+func (k Keymap) names(actions ...Action) string {
+	names := []string{}
+	for _, a := range actions {
+		for _, key := range k[a] {
+			names = append(names, key.name())
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestViKeymap(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Quit()
+	m.Keymap = ViKeymap()
+	m.Commands = []Command{{Title: "One"}, {Title: "Two"}, {Title: "Three"}, {Title: "Jump"}}
+	m.Show()
+	key := func(r rune) bool {
+		return m.handleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	for _, tc := range []struct {
+		r      rune
+		cursor int
+	}{{'j', 1}, {'j', 2}, {'k', 1}, {'G', 3}, {'g', 0}} {
+		key(tc.r)
+		if m.Cursor != tc.cursor {
+			t.Fatalf("after %q expected cursor %d, got %d", tc.r, tc.cursor, m.Cursor)
+		}
+	}
+
+	//keys are text while filtering
+	key('/')
+	key('j')
+	if string(m.filter) != "j" || m.Cursor != 3 {
+		t.Fatalf("j not added to the filter %q", string(m.filter))
+	}
+	m.handleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if key('q') {
+		t.Error("q did not exit the menu")
+	}
+}
+
+func TestKeymapNames(t *testing.T) {
+	k := DefaultKeymap()
+	if n := k.names(ActionUp, ActionToggle, ActionHelp); n != "Up, Space, F1, ?" {
+		t.Errorf("unexpected names %q", n)
+	}
+	if k.names(ActionQuit) != "" {
+		t.Error("quit is not bound by default")
+	}
+}
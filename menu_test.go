@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+//reads a line of the simulation screen as text
+func screenLine(s tcell.SimulationScreen, y int) string {
+	cells, w, _ := s.GetContents()
+	line := []rune{}
+	for _, c := range cells[y*w : (y+1)*w] {
+		line = append(line, c.Runes...)
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+//starts a menu drawn on a simulation screen, the caller must Quit it
+func newTestMenu(t *testing.T) (*Menu, tcell.SimulationScreen) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, s
+}
+
+//handles events until the running command completes, sending keys while a prompt is shown
+//check is called before each key when it is not nil
+//Returns the keys that were not sent
+func waitCommand(m *Menu, s tcell.SimulationScreen, keys []*tcell.EventKey, check func()) []*tcell.EventKey {
+	for m.Busy() {
+		m.handleEvent(s.PollEvent())
+		for m.view == viewPrompt && len(keys) > 0 {
+			if check != nil {
+				check()
+			}
+			m.handleEvent(keys[0])
+			keys = keys[1:]
+		}
+	}
+	return keys
+}
+
+//runs a command on a new test menu until it completes, answering its prompts with keys
+//The caller must Quit the menu
+func runCommand(t *testing.T, c *Command, keys ...*tcell.EventKey) (*Menu, tcell.SimulationScreen) {
+	m, s := newTestMenu(t)
+	m.RunCommand(c)
+	if keys = waitCommand(m, s, keys, nil); len(keys) > 0 {
+		t.Errorf("%d keys not sent, prompts not shown", len(keys))
+	}
+	return m, s
+}
+
+func TestNewMenuWithScreen(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Title = "Test"
+	m.Commands = []Command{{Title: "One"}, {Title: "Two"}}
+	m.Show()
+	if l := screenLine(s, 0); l != "  Test" {
+		t.Errorf("unexpected title line %q", l)
+	}
+
+	go m.EventManager()
+	s.PostEventWait(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	s.PostEventWait(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	<-m.Wait
+	if m.Cursor != 1 {
+		t.Errorf("expected cursor on second command, got %d", m.Cursor)
+	}
+	m.Quit()
+}
+
+func TestRunRestoresScreenOnPanic(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	//a nil style panics when the menu is drawn
+	m.p.style = nil
+	var stack bytes.Buffer
+	panicOutput = &stack
+	defer func() { panicOutput = os.Stderr }()
+	if err := m.Run(); err == nil {
+		t.Error("expected panic to be returned")
+	}
+	if w, h := s.Size(); w != 0 || h != 0 {
+		t.Error("screen was not finalised")
+	}
+	if !strings.HasPrefix(stack.String(), "panic: ") {
+		t.Errorf("stack not printed: %q", stack.String())
+	}
+}
+
+func TestRunCommandRecoversPanic(t *testing.T) {
+	c := Command{
+		Title: "Boom",
+		Execute: func(c *Command, ch chan string) {
+			ch <- "going"
+			panic("boom")
+		},
+	}
+	m, s := runCommand(t, &c)
+	defer m.Quit()
+	e, ok := c.Error.(*PanicError)
+	if !ok || e.Value != "boom" || len(e.Stack) == 0 {
+		t.Fatalf("expected panic error, got %v", c.Error)
+	}
+	if l := screenLine(s, 2); !strings.Contains(l, "panic: boom") {
+		t.Errorf("panic not shown in result %q", l)
+	}
+}
+
+func TestQuickSelect(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Quit()
+	m.Commands = []Command{
+		{Title: "Alpha", Optional: true},
+		{Title: "Beta", Optional: true, Hotkey: 'T'},
+		{Title: "Gamma", Optional: true, Hotkey: '1'},
+	}
+	m.Show()
+	//hotkeys are underlined in the title, after the check box
+	if _, _, style, _ := s.GetContent(2+4+2, 1); style != DefaultStyle().Default.Underline(true) {
+		t.Error("hotkey not underlined")
+	}
+
+	m.handleEvent(tcell.NewEventKey(tcell.KeyRune, 't', tcell.ModNone))
+	if m.Cursor != 1 || !m.Commands[1].Selected {
+		t.Errorf("hotkey did not select Beta, cursor %d", m.Cursor)
+	}
+	//hotkeys take precedence over digits
+	m.handleEvent(tcell.NewEventKey(tcell.KeyRune, '1', tcell.ModNone))
+	if m.Cursor != 2 || !m.Commands[2].Selected {
+		t.Errorf("hotkey did not select Gamma, cursor %d", m.Cursor)
+	}
+	m.handleEvent(tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModNone))
+	if m.Cursor != 1 || m.Commands[1].Selected {
+		t.Errorf("digit did not toggle Beta, cursor %d", m.Cursor)
+	}
+	m.handleEvent(tcell.NewEventKey(tcell.KeyRune, '9', tcell.ModNone))
+	if m.Cursor != 1 {
+		t.Error("digit out of range moved the cursor")
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	c := Command{
+		Cli: "./deploy.sh -v",
+		Args: []Argument{
+			{Name: "env", Envar: "DEPLOY_ENV", Value: "prod"},
+			{Name: "force", IsFlag: true, IsBoolean: true, Valuebool: true},
+			{Name: "dry", IsBoolean: true},
+			{Name: "message", Value: "it's done"},
+			{Name: "tag"},
+		},
+	}
+	want := `DEPLOY_ENV=prod ./deploy.sh -v -force -message 'it'\''s done' -tag ''`
+	if got := c.commandLine(); got != want {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+//Declarative definition of a Menu, so a menu can be shipped as a file next to the scripts it runs
+//Fields map to the Command and Argument structs by name (case insensitive), for example:
+// {
+//   "title": "Ops",
+//   "commands": [
+//     {"title": "Deploy", "cli": "./deploy.sh", "printout": true,
+//      "args": [{"title": "Version", "name": "version", "isflag": true}]}
+//   ]
+// }
+//The same menu can be written in YAML, in a file ending in .yaml or .yml
+//Theme is the name of a built-in style or the path to a theme file, relative to the menu file
+//Commands always run with the default OSCmdHandler
+type MenuFile struct {
+	Title       string
+	Description string
+	Theme       string
+	Commands    []Command
+	Dir         string `json:"-"` //directory the file was read from
+}
+
+//Reads and parses a menu file, files ending in .yaml or .yml are YAML and any other JSON
+func ReadMenuFile(path string) (*MenuFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if b, err = yamlToJSON(b); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	f := &MenuFile{}
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+	f.Dir = filepath.Dir(path)
+	return f, nil
+}
+
+//converts a YAML document to JSON, so YAML files map to the structs the same way as JSON
+func yamlToJSON(b []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonValue(v))
+}
+
+//replaces the maps decoded from YAML, keyed by any value, with maps keyed by strings
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, val := range v {
+			m[fmt.Sprint(k)] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonValue(v[i])
+		}
+	}
+	return v
+}
+
+//Returns the style for the file's theme, the default style if it has none
+func (f *MenuFile) Style() (*Style, error) {
+	if f.Theme == "" {
+		return DefaultStyle(), nil
+	}
+	if _, ok := Themes[f.Theme]; ok || filepath.IsAbs(f.Theme) {
+		return LoadTheme(f.Theme)
+	}
+	return LoadTheme(filepath.Join(f.Dir, f.Theme))
+}
+
+//Copies the definitions in the file to a menu
+func (f *MenuFile) Apply(m *Menu) {
+	m.Title = f.Title
+	m.Description = f.Description
+	m.Commands = make([]Command, len(f.Commands))
+	copy(m.Commands, f.Commands)
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestReadMenuFile(t *testing.T) {
+	f, err := ReadMenuFile("./sampleapp/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Title != "Test" || f.Dir != "sampleapp" {
+		t.Errorf("unexpected menu %q in %q", f.Title, f.Dir)
+	}
+	if len(f.Commands) != 4 {
+		t.Fatalf("expected 4 commands got %d", len(f.Commands))
+	}
+	args := f.Commands[2].Args
+	if len(args) != 3 || !args[0].IsFlag || !args[0].IsBoolean || args[1].Name != "second" || !args[2].IsBoolean {
+		t.Errorf("arguments not parsed: %+v", args)
+	}
+	if !f.Commands[0].PrintOut {
+		t.Error("printout not parsed")
+	}
+}
+
+func TestReadMenuFileMissing(t *testing.T) {
+	if _, err := ReadMenuFile("./sampleapp/nofile.json"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestReadMenuFileYAML(t *testing.T) {
+	y, err := ReadMenuFile("./sampleapp/menu.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := ReadMenuFile("./sampleapp/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//only the descriptions, which say how to run each file, differ
+	y.Description, j.Description = "", ""
+	if !reflect.DeepEqual(y, j) {
+		t.Errorf("YAML menu differs from JSON menu:\n%+v\n%+v", y, j)
+	}
+	if _, err := ReadMenuFile("./sampleapp/theme.json"); err != nil {
+		t.Errorf("JSON file not read as JSON: %v", err)
+	}
+}
+
+func TestHotkeyJSON(t *testing.T) {
+	var c Command
+	if err := json.Unmarshal([]byte(`{"title": "Deploy", "hotkey": "d"}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hotkey != 'd' {
+		t.Errorf("unexpected hotkey %q", c.Hotkey)
+	}
+	if err := json.Unmarshal([]byte(`{"hotkey": "dd"}`), &c); err == nil {
+		t.Error("expected error for long hotkey")
+	}
+}
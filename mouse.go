@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+//Mouse support, enabled with Menu.Mouse
+//Click selects a command and double click runs it, the wheel moves through menus and
+//scrolls the output of commands, and the bottom bar shows clickable buttons
+
+//Time between two clicks on a command to run it
+const DoubleClickTime = 500 * time.Millisecond
+
+//labels of the bottom bar buttons
+const (
+	buttonRun  = "Run"
+	buttonBack = "Back"
+	buttonQuit = "Quit"
+)
+
+//clickable button in the bottom bar
+type button struct {
+	label  string
+	x0, x1 int
+}
+
+//draws buttons on the right of the bottom bar if the mouse is enabled and the bar is shown
+func (m *Menu) drawButtons(labels ...string) {
+	m.buttons = nil
+	if !m.Mouse || !m.BottomBar {
+		return
+	}
+	w, h := m.p.Screen().Size()
+	x := w - m.p.style.Indent
+	for i := len(labels) - 1; i >= 0; i-- {
+		text := "[" + labels[i] + "]"
+		x -= len(text) + 1
+		m.p.putc(m.p.style.Hightlight, x, h-1, text)
+		m.buttons = append(m.buttons, button{label: labels[i], x0: x, x1: x + len(text)})
+	}
+}
+
+//gets the button at a screen position, or an empty label
+func (m *Menu) buttonAt(x, y int) string {
+	_, h := m.p.Screen().Size()
+	if y != h-1 {
+		return ""
+	}
+	for _, b := range m.buttons {
+		if x >= b.x0 && x < b.x1 {
+			return b.label
+		}
+	}
+	return ""
+}
+
+//handles a mouse event, returns false when the user exits the menu
+func (menu *Menu) mouseEvent(ev *tcell.EventMouse) bool {
+	x, y := ev.Position()
+	down := ev.Buttons()&tcell.Button1 != 0
+	pressed := down && !menu.mouseDown
+	menu.mouseDown = down
+
+	switch {
+	case ev.Buttons()&tcell.WheelUp != 0:
+		menu.scrollView(-1)
+	case ev.Buttons()&tcell.WheelDown != 0:
+		menu.scrollView(1)
+	case pressed:
+		if b := menu.buttonAt(x, y); b != "" {
+			return menu.pressButton(b)
+		}
+		if split := menu.splitColumn(); menu.view == viewMenu && (split == 0 || x < split) {
+			menu.clickCommand(y, ev.When())
+		}
+	}
+	return true
+}
+
+//selects the command at a row, running it on double click
+func (menu *Menu) clickCommand(y int, when time.Time) {
+	i, ok := menu.rows[y]
+	if !ok {
+		return
+	}
+	double := i == menu.Cursor && when.Sub(menu.lastClick) < DoubleClickTime
+	menu.Cursor = i
+	menu.lastClick = when
+	menu.Show()
+	if double {
+		menu.lastClick = time.Time{}
+		menu.activate()
+	}
+}
+
+//acts on a bottom bar button, returns false when the user exits the menu
+func (menu *Menu) pressButton(label string) bool {
+	switch label {
+	case buttonRun:
+		menu.activate()
+	case buttonQuit:
+		return false
+	case buttonBack:
+		if menu.view == viewPrompt {
+			menu.answerPrompt(promptReply{canceled: true})
+		} else {
+			menu.Show()
+		}
+	}
+	return true
+}
+
+//moves through the menu or scrolls the output of a command
+func (menu *Menu) scrollView(delta int) {
+	switch menu.view {
+	case viewMenu:
+		menu.moveCursor(delta, false)
+		menu.Show()
+	case viewResult:
+		menu.scrollResult(3 * delta)
+	}
+}
+
+//scrolls the output of a command by a number of lines
+func (menu *Menu) scrollResult(delta int) {
+	menu.resultScroll += delta
+	menu.ShowResult(menu.command)
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/gdamore/tcell"
 	"github.com/gdamore/tcell/encoding"
-	"github.com/mattn/go-runewidth"
 	"os"
 )
 
@@ -25,6 +24,7 @@ type Style struct {
 	H1         tcell.Style
 	Input      tcell.Style
 	Disable    tcell.Style
+	monochrome bool //colors are off, escape sequences in command output only set attributes
 }
 
 //returns default style
@@ -40,38 +40,97 @@ func DefaultStyle() *Style {
 	}
 }
 
-//returns printing object
+//returns printing object, exits if the screen cannot be initialised
 func NewPrinting(s tcell.Screen, style *Style) *Printing {
+	p, e := NewPrintingScreen(s, style)
+	if e != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", e)
+		os.Exit(1)
+	}
+	return p
+}
+
+//returns printing object for a screen, or the error initialising it
+func NewPrintingScreen(s tcell.Screen, style *Style) (*Printing, error) {
 	encoding.Register()
 
 	if e := s.Init(); e != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", e)
-		os.Exit(1)
+		return nil, e
 	}
-	s.SetStyle(tcell.StyleDefault.
-		Foreground(tcell.ColorWhite).
-		Background(tcell.Color17))
+	if style == nil {
+		style = DefaultStyle()
+	}
+	style = screenStyle(s, style)
+	//cells not drawn take the theme background
+	s.SetStyle(style.Default)
 	s.Clear()
 
 	return &Printing{
 		s:     s,
 		style: style,
+	}, nil
+}
+
+//Finalises the screen so another program can use the terminal, until Resume is called
+func (p *Printing) Suspend() {
+	p.s.Fini()
+	p.suspended = true
+}
+
+//Gets the terminal back after Suspend, on a new screen as finalised ones cannot be used again
+//Simulation screens can be initialised again, so they are kept
+func (p *Printing) Resume() error {
+	s := p.s
+	if _, ok := s.(tcell.SimulationScreen); !ok {
+		var err error
+		if s, err = tcell.NewScreen(); err != nil {
+			return err
+		}
 	}
+	if err := s.Init(); err != nil {
+		return err
+	}
+	s.SetStyle(p.style.Default)
+	s.Clear()
+	p.s = s
+	p.suspended = false
+	return nil
 }
 
 type Printing struct {
-	s       tcell.Screen
-	Cursor  int
-	xcursor int
-	style   *Style
+	s         tcell.Screen
+	Cursor    int
+	Hang      int  //extra indent of wrapped lines
+	Truncate  bool //cuts lines that do not fit with an ellipsis instead of wrapping them
+	xcursor   int
+	left      int //first column of the pane
+	right     int //column where the pane ends, 0 is the screen width
+	style     *Style
+	suspended bool //the screen has been finalised by Suspend
 }
 
-//Clears screen and goes back to the top
+//Clears screen and goes back to the top, printing on the full width
 func (p *Printing) Clear() {
 	p.s.Clear()
+	p.Pane(0, 0)
 	p.Top()
 }
 
+//limits printing to the columns from left to right, right 0 is the screen width
+func (p *Printing) Pane(left, right int) {
+	p.left = left
+	p.right = right
+}
+
+//gets the column where the pane ends
+func (p *Printing) paneRight() int {
+	if p.right > 0 {
+		return p.right
+	}
+	w, _ := p.s.Size()
+	return w
+}
+
 //gets the screen object
 func (p *Printing) Screen() tcell.Screen {
 	return p.s
@@ -106,131 +165,133 @@ func (p *Printing) Return() {
 //adds a ln with a return at the end. hightlight uses the highlight style for the full line text
 func (p *Printing) Putln(str string, highlight bool) {
 	if highlight {
-		p.Cursor = p.puts(p.style.Hightlight, p.style.Indent, p.Cursor, str)
+		p.Cursor = p.puts(p.style.Hightlight, p.left+p.style.Indent, p.Cursor, str)
 	} else {
-		p.Cursor = p.puts(p.style.Default, p.style.Indent, p.Cursor, str)
+		p.Cursor = p.puts(p.style.Default, p.left+p.style.Indent, p.Cursor, str)
+	}
+	p.Cursor++
+}
+
+//same as Putln, underlining the runes whose index is in marked, such as hotkeys
+func (p *Printing) PutlnMarked(str string, highlight bool, marked map[int]bool) {
+	style := p.style.Default
+	if highlight {
+		style = p.style.Hightlight
 	}
+	p.Cursor = p.put(style, p.left+p.style.Indent, p.Cursor, str, marked, true)
+	p.Cursor++
+}
+
+//same as Putln with a style for each rune of str, runes without one use the default style
+func (p *Printing) putlnStyled(str string, styles []tcell.Style) {
+	p.Cursor = p.draw(p.style.Default, p.left+p.style.Indent, p.Cursor, str, func(n int) tcell.Style {
+		if n >= 0 && n < len(styles) {
+			return styles[n]
+		}
+		return p.style.Default
+	}, true)
 	p.Cursor++
 }
 
 //add a line disabled style
 func (p *Printing) PutlnDisable(str string) {
-	p.Cursor = p.puts(p.style.Disable, p.style.Indent, p.Cursor, str)
+	p.Cursor = p.puts(p.style.Disable, p.left+p.style.Indent, p.Cursor, str)
 	p.Cursor++
 }
 
 //same as Putln but continues on x
 func (p *Printing) Put(str string, highlight bool) {
 	if highlight {
-		p.Cursor = p.puts(p.style.Hightlight, p.style.Indent+p.xcursor, p.Cursor, str)
+		p.Cursor = p.puts(p.style.Hightlight, p.left+p.style.Indent+p.xcursor, p.Cursor, str)
 	} else {
-		p.Cursor = p.puts(p.style.Default, p.style.Indent+p.xcursor, p.Cursor, str)
+		p.Cursor = p.puts(p.style.Default, p.left+p.style.Indent+p.xcursor, p.Cursor, str)
 	}
 }
 
 //prints string but does not move cursor
 func (p *Printing) PutEcho(str string, style tcell.Style) {
 
-	p.puts(style, p.style.Indent+p.xcursor, p.Cursor, str)
+	p.puts(style, p.left+p.style.Indent+p.xcursor, p.Cursor, str)
 }
 
 //putsln with H1 style
 func (p *Printing) PutH1(str string, highlight bool) {
-	p.Cursor = p.puts(p.style.H1, p.style.Indent, p.Cursor, str)
+	p.Cursor = p.puts(p.style.H1, p.left+p.style.Indent, p.Cursor, str)
 	p.Cursor++
 }
 
 //prints the bottome bar
 func (p *Printing) BottomBar(str string) {
 	_, y := p.s.Size()
+	left, right := p.left, p.right
+	p.Pane(0, 0)
 	p.puts(p.style.Menu, 0, y-1, "  "+str)
+	p.Pane(left, right)
 }
 
 //puts a string line without filling the end spaces
 func (p *Printing) putc(style tcell.Style, x, y int, str string) int {
-	i := 0
-	var deferred []rune
-	dwidth := 0
-	xScreen, _ := p.s.Size()
-
-	for _, r := range str {
-		if x+i >= xScreen-p.style.Indent {
-			i = 0
-			y++
-		}
-		switch runewidth.RuneWidth(r) {
-		case 0:
-			if len(deferred) == 0 {
-				deferred = append(deferred, ' ')
-				dwidth = 1
-			}
-		case 1:
-			if len(deferred) != 0 {
-				p.s.SetContent(x+i, y, deferred[0], deferred[1:], style)
-				i += dwidth
-			}
-			deferred = nil
-			dwidth = 1
-		case 2:
-			if len(deferred) != 0 {
-				p.s.SetContent(x+i, y, deferred[0], deferred[1:], style)
-				i += dwidth
-			}
-			deferred = nil
-			dwidth = 2
-		}
-		deferred = append(deferred, r)
-	}
-	if len(deferred) != 0 {
-		p.s.SetContent(x+i, y, deferred[0], deferred[1:], style)
-		i += dwidth
-	}
-	return y
+	return p.put(style, x, y, str, nil, false)
 }
 
 //puts a line filling the trailing spaces to the end with the same style
 func (p *Printing) puts(style tcell.Style, x, y int, str string) int {
-	i := 0
-	var deferred []rune
-	dwidth := 0
-	xScreen, _ := p.s.Size()
-
-	for _, r := range str {
-		if x+i >= xScreen-p.style.Indent {
-			i = 0
+	return p.put(style, x, y, str, nil, true)
+}
+
+//puts a string wrapping words at the indent on the right, runes whose index is in marked are underlined
+//wrapped lines line up with the text after the leading spaces, plus Hang columns
+//with Truncate set the string is cut to a single line instead
+//if fill is set the trailing spaces to the end are filled with the same style
+func (p *Printing) put(style tcell.Style, x, y int, str string, marked map[int]bool, fill bool) int {
+	return p.draw(style, x, y, str, func(n int) tcell.Style {
+		return markStyle(style, n >= 0 && marked[n])
+	}, fill)
+}
+
+//same as put, styleAt gets the style of the rune at an index of str and style fills the line
+func (p *Printing) draw(style tcell.Style, x, y int, str string, styleAt func(n int) tcell.Style, fill bool) int {
+	xScreen := p.paneRight()
+	width := xScreen - p.style.Indent - x
+	cells := toCells(str)
+	hang := leadingSpaces(cells) + p.Hang
+	if hang > width/2 {
+		hang = 0
+	}
+	var lines [][]cell
+	if p.Truncate {
+		lines = [][]cell{truncateCells(cells, width)}
+	} else {
+		lines = wrapCells(cells, width, hang)
+	}
+
+	for n, line := range lines {
+		if n > 0 {
 			y++
 		}
-		switch runewidth.RuneWidth(r) {
-		case 0:
-			if len(deferred) == 0 {
-				deferred = append(deferred, ' ')
-				dwidth = 1
-			}
-		case 1:
-			if len(deferred) != 0 {
-				p.s.SetContent(x+i, y, deferred[0], deferred[1:], style)
-				i += dwidth
-			}
-			deferred = nil
-			dwidth = 1
-		case 2:
-			if len(deferred) != 0 {
-				p.s.SetContent(x+i, y, deferred[0], deferred[1:], style)
-				i += dwidth
-			}
-			deferred = nil
-			dwidth = 2
+		i := 0
+		if n > 0 {
+			i = hang
+		}
+		for j := 0; fill && j < i; j++ {
+			p.s.SetContent(x+j, y, ' ', nil, style)
+		}
+		for _, c := range line {
+			p.s.SetContent(x+i, y, c.r, c.comb, styleAt(c.index))
+			i += c.width
+		}
+		for fill && x+i < xScreen {
+			p.s.SetContent(x+i, y, ' ', nil, style)
+			i++
 		}
-		deferred = append(deferred, r)
-	}
-	if len(deferred) != 0 {
-		p.s.SetContent(x+i, y, deferred[0], deferred[1:], style)
-		i += dwidth
-	}
-	for i < xScreen {
-		p.s.SetContent(x+i, y, ' ', nil, style)
-		i++
 	}
 	return y
+}
 
+//gets the style for a marked rune
+func markStyle(style tcell.Style, marked bool) tcell.Style {
+	if marked {
+		return style.Underline(true)
+	}
+	return style
 }
@@ -2,43 +2,130 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/gdamore/tcell"
 )
 
+//Frames of the spinner shown for work of unknown length
+var DefaultSpinner = []string{" | ", " / ", " - ", " \\ "}
+
+//Progress shown while a command runs, a bar for work of known length or a spinner
+//It only draws from the menu event loop, Start posts tick events to the screen every
+//Interval milliseconds and the menu calls Tick for each of them
 type ProgressBar struct {
 	Interval int
+	Frames   []string //spinner frames, drawn in turn
+	Done     int64    //units of work done, see Update
+	Total    int64    //units of work, 0 when unknown
 	p        *Printing
-	ticker   *time.Ticker
+	stop     chan struct{}
+	frame    int
 	Text     string
+	percent  bool      //progress was reported as a percent, it has no rate
+	start    time.Time //when the work started, for the rate and time left
 }
 
 func NewProgressBar(p *Printing) *ProgressBar {
-	return &ProgressBar{p: p, Interval: 500, Text: "Please wait"}
+	return &ProgressBar{p: p, Interval: 500, Text: "Please wait", Frames: DefaultSpinner}
 }
 
+//Starts posting tick events
 func (p *ProgressBar) Start() {
-	ticker := time.NewTicker(time.Millisecond * time.Duration(p.Interval))
-	p.ticker = ticker
-	x, y := p.p.Screen().Size()
+	p.Stop()
+	p.start = time.Now()
+	p.stop = make(chan struct{})
+	go postTicks(p.p.Screen(), time.Millisecond*time.Duration(p.Interval), p.stop)
+}
+
+//Sets how much work is done, of a total that is 0 when unknown, and the text shown if not empty
+func (p *ProgressBar) Update(done, total int64, text string) {
+	p.Done, p.Total = done, total
+	if text != "" {
+		p.Text = text
+	}
+}
+
+//Moves the spinner forward and draws it
+func (p *ProgressBar) Tick() {
+	p.frame++
+	p.Draw()
+	p.p.Show()
+}
 
+//Draws the progress bar in the middle of the screen
+func (p *ProgressBar) Draw() {
+	x, y := p.p.Screen().Size()
+	for row := y/2 - 1; row <= y/2+1; row++ {
+		p.p.puts(p.p.style.Default, 0, row, "")
+	}
 	p.p.putc(p.p.style.Default, x/2-len(p.Text)/2+1, y/2-1, p.Text)
-	i := 0
-	bar := []string{" | ", " / ", " - ", " \\ "}
-	for ok := true; ok; {
-		if i >= len(bar) {
-			i = 0
+	if p.Total <= 0 {
+		frames := p.Frames
+		if len(frames) == 0 {
+			frames = DefaultSpinner
 		}
-		p.p.putc(styleTextHighlight, x/2, y/2, bar[i])
-		p.p.Show()
-		i++
-		_, ok = <-ticker.C
+		p.p.putc(p.p.style.Hightlight, x/2, y/2, frames[p.frame%len(frames)])
+		return
+	}
+
+	width := min(max(x-2*p.p.style.Indent-7, 0), 50)
+	filled := int(int64(width) * p.done() / p.Total)
+	percent := fmt.Sprintf(" %3d%%", 100*p.done()/p.Total)
+	left := x/2 - (width+len(percent))/2
+	p.p.putc(p.p.style.Default, left, y/2, strings.Repeat(string(tcell.RuneBlock), filled))
+	p.p.putc(p.p.style.Disable, left+filled, y/2, strings.Repeat(string(tcell.RuneBoard), width-filled))
+	p.p.putc(p.p.style.Default, left+width, y/2, percent)
+	if stats := p.stats(time.Since(p.start)); stats != "" {
+		p.p.putc(p.p.style.Default, x/2-len(stats)/2, y/2+1, stats)
+	}
+}
+
+//gets the work done, within the total
+func (p *ProgressBar) done() int64 {
+	switch {
+	case p.Done < 0:
+		return 0
+	case p.Done > p.Total:
+		return p.Total
 	}
-	fmt.Println("exit")
+	return p.Done
+}
 
+//gets the units done, rate and time left after running for elapsed, such as "30/120  2.0/s  ETA 45s"
+func (p *ProgressBar) stats(elapsed time.Duration) string {
+	done := p.done()
+	s := []string{}
+	if !p.percent {
+		s = append(s, fmt.Sprintf("%d/%d", done, p.Total))
+		if elapsed >= time.Second {
+			s = append(s, fmt.Sprintf("%.1f/s", float64(done)/elapsed.Seconds()))
+		}
+	}
+	if done > 0 && done < p.Total && elapsed >= time.Second {
+		eta := time.Duration(float64(elapsed) * float64(p.Total-done) / float64(done))
+		s = append(s, "ETA "+eta.Round(time.Second).String())
+	}
+	return strings.Join(s, "  ")
 }
 
+//Stops posting tick events
 func (p *ProgressBar) Stop() {
-	if p.ticker != nil {
-		p.ticker.Stop()
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
 	}
 }
+
+//Reports the progress of a command from its handler, done of total units of work and a text
+//to show, which can be empty to keep the last one. The menu draws a bar with the percent,
+//rate and time left, or a spinner while total is 0
+func (c *Command) Progress(done, total int64, text string) {
+	c.Emit(Event{Kind: EventProgress, Done: done, Total: total, Text: text})
+}
+
+//Same as Progress with the percent of the work done, from 0 to 100
+func (c *Command) ProgressPercent(percent float64, text string) {
+	c.Emit(Event{Kind: EventProgress, Done: int64(percent * 100), Total: 100 * 100, Text: text, percent: true})
+}
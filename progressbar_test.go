@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestProgressStats(t *testing.T) {
+	p := &ProgressBar{Done: 30, Total: 120}
+	if s := p.stats(10 * time.Second); s != "30/120  3.0/s  ETA 30s" {
+		t.Errorf("unexpected stats %q", s)
+	}
+	if s := p.stats(0); s != "30/120" {
+		t.Errorf("unexpected stats before a second %q", s)
+	}
+	p = &ProgressBar{Done: 5000, Total: 10000, percent: true}
+	if s := p.stats(time.Minute); s != "ETA 1m0s" {
+		t.Errorf("unexpected stats for a percent %q", s)
+	}
+	p = &ProgressBar{Done: 150, Total: 120}
+	if p.done() != 120 {
+		t.Error("done not limited to the total")
+	}
+}
+
+func TestCommandProgress(t *testing.T) {
+	m, s := newTestMenu(t)
+	defer m.Quit()
+	release := make(chan struct{})
+	c := Command{
+		Title: "Copy",
+		Execute: func(c *Command, ch chan string) {
+			defer close(ch)
+			c.Progress(30, 120, "Copying files")
+			<-release
+		},
+	}
+	m.RunCommand(&c)
+	for m.progress.Total == 0 {
+		m.handleEvent(s.PollEvent())
+	}
+	_, h := s.Size()
+	if l := screenLine(s, h/2-1); !strings.Contains(l, "Copying files") {
+		t.Errorf("text not shown: %q", l)
+	}
+	if l := screenLine(s, h/2); !strings.HasSuffix(l, "░  25%") || !strings.Contains(l, "█") {
+		t.Errorf("bar not shown: %q", l)
+	}
+	if l := screenLine(s, h/2+1); !strings.Contains(l, "30/120") {
+		t.Errorf("stats not shown: %q", l)
+	}
+	close(release)
+	waitCommand(m, s, nil, nil)
+}
+
+func TestProgressNotRunning(t *testing.T) {
+	c := Command{}
+	done := make(chan struct{})
+	go func() {
+		//no menu is running the command, progress is ignored
+		c.ProgressPercent(50, "half")
+		c.Progress(1, 2, "")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("progress blocked without a menu")
+	}
+	if len(c.Events) != 0 {
+		t.Errorf("progress recorded without a menu: %v", c.Events)
+	}
+}
+
+func TestProgressNarrow(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	p, err := NewPrintingScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	bar := NewProgressBar(p)
+	bar.Update(1, 2, "")
+	for _, w := range []int{10, 5, 1} {
+		s.SetSize(w, 10)
+		p.Show()
+		bar.Draw()
+	}
+}
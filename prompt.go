@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+//Prompts let a running command ask the user a question. The handler blocks while the menu
+//shows the question in place of the progress, and goes on running once it is answered
+
+//Asks the user questions from the handler of a running command, see Command.Prompter
+//Each method blocks the handler until the user answers, and returns ErrPromptCanceled if
+//the user cancels the question instead
+type Prompter interface {
+	//Asks a yes or no question
+	Confirm(message string) (bool, error)
+	//Asks for a line of text, value is the default answer
+	Input(message, value string) (string, error)
+	//Asks for a line of text that is not shown, like a password
+	Secret(message string) (string, error)
+	//Asks to choose one of the choices, returns its index
+	Choose(message string, choices []string) (int, error)
+}
+
+//Returned by a Prompter when the user cancels a question, or when no menu or RunHeadless is
+//running the command
+var ErrPromptCanceled = errors.New("tui: prompt canceled")
+
+//Gets the Prompter handlers use to ask the user questions. With a menu the question is shown
+//in place of the progress, with RunHeadless it is answered from the standard input
+func (c *Command) Prompter() Prompter {
+	return commandPrompter{c}
+}
+
+//Prompter asking through the menu or RunHeadless running a command
+type commandPrompter struct {
+	c *Command
+}
+
+func (p commandPrompter) Confirm(message string) (bool, error) {
+	r := p.c.ask(&prompt{kind: promptConfirm, message: message})
+	return r.value == "yes", r.err()
+}
+
+func (p commandPrompter) Input(message, value string) (string, error) {
+	r := p.c.ask(&prompt{kind: promptText, message: message, value: value})
+	return r.value, r.err()
+}
+
+func (p commandPrompter) Secret(message string) (string, error) {
+	r := p.c.ask(&prompt{kind: promptSecret, message: message})
+	return r.value, r.err()
+}
+
+func (p commandPrompter) Choose(message string, choices []string) (int, error) {
+	if len(choices) == 0 {
+		return -1, errors.New("tui: no choices")
+	}
+	r := p.c.ask(&prompt{kind: promptChoice, message: message, choices: choices})
+	if r.canceled {
+		return -1, ErrPromptCanceled
+	}
+	return r.index, nil
+}
+
+//kind of answer a prompt takes
+type promptKind int
+
+const (
+	promptText promptKind = iota
+	promptSecret
+	promptConfirm
+	promptChoice
+)
+
+//question asked by a running command
+type prompt struct {
+	kind    promptKind
+	message string
+	value   string   //default answer, yes or no for a confirm
+	choices []string //answers of a choice
+	reply   chan promptReply
+}
+
+//answer to a prompt
+type promptReply struct {
+	value    string
+	index    int //of the choice taken
+	canceled bool
+}
+
+//gets ErrPromptCanceled if the prompt was canceled
+func (r promptReply) err() error {
+	if r.canceled {
+		return ErrPromptCanceled
+	}
+	return nil
+}
+
+//asks the user a question from the handler, waiting for the answer
+//it is canceled if no menu or RunHeadless is running the command
+func (c *Command) ask(p *prompt) promptReply {
+	if c.events == nil {
+		return promptReply{canceled: true}
+	}
+	p.reply = make(chan promptReply, 1)
+	c.Emit(Event{prompt: p})
+	return <-p.reply
+}
+
+//shows a prompt from the running command, others are canceled
+func (m *Menu) showPrompt(c *Command, p *prompt) {
+	if m.view != viewRunning || m.command != c {
+		p.reply <- promptReply{canceled: true}
+		return
+	}
+	m.prompt = p
+	m.promptCursor = 0
+	m.runeBuffer = []rune(p.value)
+	if p.kind == promptSecret {
+		m.runeBuffer = []rune{}
+	}
+	m.view = viewPrompt
+	m.drawPrompt()
+}
+
+//answers the prompt shown and goes back to the progress of the command
+func (m *Menu) answerPrompt(r promptReply) {
+	m.prompt.reply <- r
+	m.prompt = nil
+	m.runeBuffer = []rune{}
+	m.view = viewRunning
+	m.drawRunning()
+}
+
+//draws the prompt shown, like the prompt for an argument
+func (m *Menu) drawPrompt() {
+	c, p := m.command, m.prompt
+	m.p.Clear()
+	m.printPageHearder(c.BreadCrum(), c.Description)
+	m.p.Putln(p.message, false)
+	bar := m.ValueText
+	switch p.kind {
+	case promptText:
+		m.p.PutEcho(string(append(m.runeBuffer, tcell.RuneBlock)), m.p.style.Input)
+	case promptSecret:
+		m.p.PutEcho(strings.Repeat("*", len(m.runeBuffer))+string(tcell.RuneBlock), m.p.style.Input)
+	case promptConfirm:
+		bar = m.BoolText
+	case promptChoice:
+		m.p.Return()
+		for i, choice := range p.choices {
+			if m.p.Cursor >= m.lastRow() {
+				break
+			}
+			m.p.Putln(fmt.Sprintf("%d. %s", i+1, choice), i == m.promptCursor)
+		}
+		bar = m.ChoiceText
+	}
+	if m.BottomBar {
+		m.p.BottomBar(bar)
+	}
+	m.drawButtons(buttonBack)
+	m.p.Show()
+}
+
+//handles an event while a prompt is shown
+func (m *Menu) promptEvent(ev tcell.Event) {
+	ek, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return
+	}
+	p := m.prompt
+	if a, ok := m.action(ek); ok {
+		switch a {
+		case ActionBack:
+			m.answerPrompt(promptReply{canceled: true})
+		case ActionSelect:
+			switch p.kind {
+			case promptText, promptSecret:
+				m.answerPrompt(promptReply{value: string(m.runeBuffer)})
+			case promptConfirm:
+				if p.value != "" {
+					m.answerPrompt(promptReply{value: p.value})
+				}
+			case promptChoice:
+				m.answerPrompt(promptReply{value: p.choices[m.promptCursor], index: m.promptCursor})
+			}
+		case ActionUp, ActionDown:
+			if p.kind == promptChoice && len(p.choices) > 0 {
+				delta := 1
+				if a == ActionUp {
+					delta = -1
+				}
+				m.promptCursor = (m.promptCursor + delta + len(p.choices)) % len(p.choices)
+				m.drawPrompt()
+			}
+		}
+		return
+	}
+	switch ek.Key() {
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if (p.kind == promptText || p.kind == promptSecret) && len(m.runeBuffer) > 0 {
+			m.runeBuffer = m.runeBuffer[:len(m.runeBuffer)-1]
+			m.drawPrompt()
+		}
+	case tcell.KeyRune:
+		r := ek.Rune()
+		switch p.kind {
+		case promptText, promptSecret:
+			m.runeBuffer = append(m.runeBuffer, r)
+			m.drawPrompt()
+		case promptConfirm:
+			switch r {
+			case 'y', 'Y':
+				m.answerPrompt(promptReply{value: "yes"})
+			case 'n', 'N':
+				m.answerPrompt(promptReply{value: "no"})
+			}
+		case promptChoice:
+			if i := int(r - '1'); i >= 0 && i < 9 && i < len(p.choices) {
+				m.answerPrompt(promptReply{value: p.choices[i], index: i})
+			}
+		}
+	}
+}
+
+//answers a prompt reading lines from r, used by RunHeadless
+//the prompt is canceled once r has no more lines
+func (p *prompt) answerFrom(r *bufio.Reader, out io.Writer) promptReply {
+	for {
+		switch p.kind {
+		case promptConfirm:
+			fmt.Fprintf(out, "%s (y/n) ", p.message)
+		case promptChoice:
+			fmt.Fprintln(out, p.message)
+			for i, choice := range p.choices {
+				fmt.Fprintf(out, "%d. %s\n", i+1, choice)
+			}
+			fmt.Fprint(out, "? ")
+		default:
+			fmt.Fprint(out, p.message+" ")
+		}
+		line, err := r.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(out)
+			return promptReply{canceled: true}
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if p.kind == promptSecret {
+			//the new line typed is not shown
+			fmt.Fprintln(out)
+		}
+		switch p.kind {
+		case promptConfirm:
+			switch strings.ToLower(line) {
+			case "y", "yes":
+				return promptReply{value: "yes"}
+			case "n", "no":
+				return promptReply{value: "no"}
+			case "":
+				if p.value != "" {
+					return promptReply{value: p.value}
+				}
+			}
+		case promptChoice:
+			if i, err := strconv.Atoi(line); err == nil && i >= 1 && i <= len(p.choices) {
+				return promptReply{value: p.choices[i-1], index: i - 1}
+			}
+		default:
+			if line == "" {
+				line = p.value
+			}
+			return promptReply{value: line}
+		}
+	}
+}
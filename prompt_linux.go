@@ -0,0 +1,25 @@
+// +build linux
+
+package tui
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+//turns off echo on terminals, returns a func that turns it back on
+func hideInput(f *os.File) func() {
+	var old syscall.Termios
+	if ioctl(f, syscall.TCGETS, uintptr(unsafe.Pointer(&old))) != nil {
+		return func() {}
+	}
+	hidden := old
+	hidden.Lflag &^= syscall.ECHO
+	if ioctl(f, syscall.TCSETS, uintptr(unsafe.Pointer(&hidden))) != nil {
+		return func() {}
+	}
+	return func() {
+		ioctl(f, syscall.TCSETS, uintptr(unsafe.Pointer(&old)))
+	}
+}
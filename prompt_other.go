@@ -0,0 +1,12 @@
+// +build !linux
+
+package tui
+
+import (
+	"os"
+)
+
+//input is always shown, terminals are left as they are
+func hideInput(f *os.File) func() {
+	return func() {}
+}
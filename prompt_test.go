@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestPrompter(t *testing.T) {
+	m, s := newTestMenu(t)
+	defer m.Quit()
+	var name, password string
+	var sure bool
+	var choice int
+	var errs []error
+	c := Command{
+		Title: "Setup",
+		Execute: func(c *Command, ch chan string) {
+			defer close(ch)
+			p := c.Prompter()
+			var err error
+			name, err = p.Input("Name?", "bob")
+			errs = append(errs, err)
+			password, err = p.Secret("Password?")
+			errs = append(errs, err)
+			sure, err = p.Confirm("Sure?")
+			errs = append(errs, err)
+			choice, err = p.Choose("Which?", []string{"a", "b", "c"})
+			errs = append(errs, err)
+			_, err = p.Input("Canceled?", "")
+			errs = append(errs, err)
+		},
+	}
+	m.RunCommand(&c)
+	keys := []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'p', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone),
+	}
+	waitCommand(m, s, keys, func() {
+		if m.prompt.kind == promptSecret && len(m.runeBuffer) == 2 {
+			if l := screenLine(s, m.p.Cursor); strings.TrimSpace(l) != "**█" {
+				t.Errorf("secret shown: %q", l)
+			}
+		}
+	})
+	if name != "box" || password != "pw" || !sure || choice != 2 {
+		t.Errorf("unexpected answers %q %q %v %d", name, password, sure, choice)
+	}
+	for i, err := range errs[:4] {
+		if err != nil {
+			t.Errorf("answer %d: %v", i, err)
+		}
+	}
+	if errs[4] != ErrPromptCanceled {
+		t.Errorf("expected a canceled prompt, got %v", errs[4])
+	}
+	if m.view != viewResult {
+		t.Error("result not shown")
+	}
+}
+
+func TestPrompterNotRunning(t *testing.T) {
+	c := Command{}
+	if _, err := c.Prompter().Confirm("Sure?"); err != ErrPromptCanceled {
+		t.Errorf("expected a canceled prompt, got %v", err)
+	}
+	if _, err := c.Prompter().Choose("Which?", nil); err == nil {
+		t.Error("expected an error without choices")
+	}
+}
@@ -0,0 +1,72 @@
+// +build linux
+
+package tui
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+//starts a command in a new pseudo terminal of w columns and h rows
+//returns the master side of the terminal, to read the command output and write its input
+func startPty(cmd *exec.Cmd, w, h int) (*os.File, error) {
+	master, slave, err := openPty()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close()
+	if err := setPtySize(master, w, h); err != nil {
+		master.Close()
+		return nil, err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	//the terminal becomes the controlling terminal of a new session, so ^C reaches the command
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+//opens a pseudo terminal pair from /dev/ptmx
+func openPty() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var n uint32
+	if err := ioctl(master, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	var unlock int32
+	if err := ioctl(master, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	slave, err := os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+//sets the size of a pseudo terminal, the command gets a SIGWINCH
+func setPtySize(f *os.File, w, h int) error {
+	ws := struct {
+		rows, cols, xpixel, ypixel uint16
+	}{uint16(h), uint16(w), 0, 0}
+	return ioctl(f, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws)))
+}
+
+func ioctl(f *os.File, req, arg uintptr) error {
+	if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, arg); e != 0 {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+// +build !linux
+
+package tui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+//Error returned by interactive commands where pseudo terminals are not supported
+var ErrNoPty = errors.New("tui: interactive commands are only supported on linux")
+
+func startPty(cmd *exec.Cmd, w, h int) (*os.File, error) {
+	return nil, ErrNoPty
+}
+
+func setPtySize(f *os.File, w, h int) error {
+	return ErrNoPty
+}
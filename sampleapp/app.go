@@ -1,6 +1,8 @@
 package main
 
 import (
+	"log"
+
 	"github.com/vtuson/tui"
 )
 
@@ -12,6 +14,7 @@ func NewTestMenu() *tui.Menu {
 	tmpcs := []tui.Command{
 		tui.Command{
 			Title:       "No Args",
+			Hotkey:      'n',
 			Cli:         "./testcommands/waitok.sh",
 			Description: "test of running a tui.Command with out arguments",
 			Success:     "Yey it works",
@@ -76,6 +79,37 @@ func NewTestMenu() *tui.Menu {
 			Success: "Yey it works",
 			Fail:    "oh, it didnt work.",
 		},
+		tui.Command{
+			Title:       "Interactive",
+			Cli:         "./testcommands/prompt.sh",
+			Description: "test of running a command that prompts in a terminal",
+			Interactive: true,
+		},
+		tui.Command{
+			Title:       "Pager",
+			Cli:         "less ./testcommands/waitok.sh",
+			Description: "test of handing the terminal to a full screen program",
+			Suspend:     true,
+		},
+		tui.Command{
+			Title:       "Workflow",
+			Cli:         "./testcommands/workflow.sh",
+			Description: "test of a script reporting progress, status and results",
+			PrintOut:    true,
+		},
+		tui.Command{
+			Title:       "Restore",
+			Cli:         "./testcommands/backup.sh",
+			Description: "test of a script asking questions while it runs",
+			PrintOut:    true,
+			Prompts:     true,
+		},
+		tui.Command{
+			Title:       "Deploy",
+			Description: "test of a Go handler asking questions while it runs",
+			Execute:     deploy,
+			PrintOut:    true,
+		},
 		tui.Command{
 			Title:   "Done cmd",
 			Disable: true,
@@ -89,8 +123,28 @@ func NewTestMenu() *tui.Menu {
 func main() {
 	menu := NewTestMenu()
 
-	menu.Show()
-	go menu.EventManager()
-	<-menu.Wait
-	menu.Quit()
+	if err := menu.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//asks where to deploy, production needs a confirmation
+func deploy(c *tui.Command, ch chan string) {
+	defer close(ch)
+	p := c.Prompter()
+	envs := []string{"staging", "production"}
+	i, err := p.Choose("Where to deploy?", envs)
+	if err != nil {
+		c.Error = err
+		return
+	}
+	if envs[i] == "production" {
+		ok, err := p.Confirm("Deploy to production?")
+		if err != nil || !ok {
+			c.Error = tui.ErrPromptCanceled
+			return
+		}
+	}
+	ch <- "Deploying to " + envs[i] + "\n"
+	c.SetResult("environment", envs[i])
 }
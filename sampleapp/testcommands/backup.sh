@@ -0,0 +1,21 @@
+#!/bin/bash
+
+#asks questions over fd 3, answers are a line of JSON like {"value":"tuesday","index":1}
+value() {
+	sed -n 's/.*"value":"\([^"]*\)".*/\1/p'
+}
+
+echo '{"type": "choice", "message": "Which backup?", "choices": ["monday", "tuesday", "wednesday"]}' >&3
+read -r reply <&3
+backup=$(echo "$reply" | value)
+if [ -z "$backup" ]; then
+	echo Canceled
+	exit 1
+fi
+echo '{"type": "confirm", "message": "Overwrite the database with the '$backup' backup?", "default": "no"}' >&3
+read -r reply <&3
+if [ "$(echo "$reply" | value)" != "yes" ]; then
+	echo Nothing restored
+	exit 0
+fi
+echo Restoring $backup
@@ -0,0 +1,4 @@
+#!/bin/bash
+
+read -p "What is your name? " NAME
+echo Hello $NAME
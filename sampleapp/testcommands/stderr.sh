@@ -0,0 +1,4 @@
+#!/bin/bash
+
+echo Checking
+echo Disk almost full >&2
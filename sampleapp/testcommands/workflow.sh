@@ -0,0 +1,13 @@
+#!/bin/bash
+
+echo "::status Migrating"
+COUNTER=0
+while [  $COUNTER -lt 4 ]; do
+        echo Migrating table $COUNTER
+        let COUNTER=COUNTER+1
+        echo "::progress $COUNTER/4 Migrating tables"
+	sleep 0.5
+done
+echo "::warning file=db.sql,line=12::Table users has no index"
+echo "::set version=1.2.3"
+echo "::status Migrated"
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+//Commands run by the OSCmdHandler with Prompts set can ask the user questions while they run
+//They write a request as a line of JSON on fd 3 and read the answer from it, also a line of JSON:
+//  {"type": "choice", "message": "Which backup?", "choices": ["monday", "tuesday"]}
+//  {"value": "tuesday", "index": 1}
+//Types are text, secret, confirm and choice. The default of a confirm is "yes" or "no" and its
+//value is one of them. Canceled prompts are answered with {"canceled": true}
+
+//Returned by OSCmdHandler for commands with Prompts where fd 3 is not available
+var ErrNoSideChannel = errors.New("tui: prompts over fd 3 are not supported on this platform")
+
+//question written by a command on fd 3
+type promptRequest struct {
+	Type    string   `json:"type"`
+	Message string   `json:"message"`
+	Default string   `json:"default,omitempty"`
+	Choices []string `json:"choices,omitempty"`
+}
+
+//answer written back to the command on fd 3
+type promptResponse struct {
+	Value    string `json:"value,omitempty"`
+	Index    *int   `json:"index,omitempty"`
+	Canceled bool   `json:"canceled,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+var promptKinds = map[string]promptKind{
+	"":        promptText,
+	"text":    promptText,
+	"secret":  promptSecret,
+	"confirm": promptConfirm,
+	"choice":  promptChoice,
+}
+
+//answers the requests of a command until rw is closed
+func (c *Command) servePrompts(rw io.ReadWriter) {
+	r := bufio.NewReader(rw)
+	enc := json.NewEncoder(rw)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			enc.Encode(c.answerRequest(line))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+//asks the user the question of a request
+func (c *Command) answerRequest(line []byte) promptResponse {
+	var req promptRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return promptResponse{Error: err.Error()}
+	}
+	kind, ok := promptKinds[req.Type]
+	if !ok {
+		return promptResponse{Error: "unknown prompt type " + req.Type}
+	}
+	if kind == promptChoice && len(req.Choices) == 0 {
+		return promptResponse{Error: "choice without choices"}
+	}
+	r := c.ask(&prompt{kind: kind, message: req.Message, value: req.Default, choices: req.Choices})
+	if r.canceled {
+		return promptResponse{Canceled: true}
+	}
+	resp := promptResponse{Value: r.value}
+	if kind == promptChoice {
+		resp.Index = &r.index
+	}
+	return resp
+}
@@ -0,0 +1,18 @@
+// +build linux
+
+package tui
+
+import (
+	"os"
+	"syscall"
+)
+
+//opens a socket pair, the child end is passed to the command as fd 3
+func openSideChannel() (parent, child *os.File, err error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	syscall.CloseOnExec(fds[0])
+	return os.NewFile(uintptr(fds[0]), "prompts"), os.NewFile(uintptr(fds[1]), "prompts"), nil
+}
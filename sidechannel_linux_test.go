@@ -0,0 +1,37 @@
+// +build linux
+
+package tui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+//runs the backup script answering its prompts with keys
+func runBackup(t *testing.T, keys ...*tcell.EventKey) *Command {
+	c := Command{Title: "Restore", Cli: "./sampleapp/testcommands/backup.sh", Prompts: true, PrintOut: true}
+	m, _ := runCommand(t, &c, keys...)
+	m.Quit()
+	return &c
+}
+
+func TestSideChannelPrompts(t *testing.T) {
+	c := runBackup(t,
+		tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModNone))
+	if out := c.output(); out != "Restoring tuesday\n" || c.Error != nil {
+		t.Errorf("unexpected output %q, error %v", out, c.Error)
+	}
+	c = runBackup(t,
+		tcell.NewEventKey(tcell.KeyRune, '3', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if out := c.output(); out != "Nothing restored\n" {
+		t.Errorf("default answer not taken: %q", out)
+	}
+	c = runBackup(t, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if out := c.output(); out != "Canceled\n" || c.Error == nil {
+		t.Errorf("prompt not canceled: %q", out)
+	}
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package tui
+
+import (
+	"os"
+)
+
+//fd 3 can not be passed to commands
+func openSideChannel() (parent, child *os.File, err error) {
+	return nil, nil, ErrNoSideChannel
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAnswerRequest(t *testing.T) {
+	c := Command{}
+	for line, want := range map[string]string{
+		"not json":                        "invalid character",
+		`{"type": "date"}`:                "unknown prompt type",
+		`{"type": "choice"}`:              "choice without choices",
+		`{"type": "text", "message": ""}`: "",
+	} {
+		r := c.answerRequest([]byte(line))
+		if want == "" && !r.Canceled || !strings.Contains(r.Error, want) {
+			t.Errorf("unexpected response to %s: %+v", line, r)
+		}
+	}
+}
+
+func TestPromptAnswerFrom(t *testing.T) {
+	var out bytes.Buffer
+	in := bufio.NewReader(strings.NewReader("maybe\n\n2\nbob\n"))
+	p := &prompt{kind: promptConfirm, message: "Sure?", value: "no"}
+	if r := p.answerFrom(in, &out); r.value != "no" {
+		t.Errorf("default not taken: %+v", r)
+	}
+	p = &prompt{kind: promptChoice, message: "Which?", choices: []string{"a", "b"}}
+	if r := p.answerFrom(in, &out); r.value != "b" || r.index != 1 {
+		t.Errorf("unexpected choice %+v", r)
+	}
+	p = &prompt{kind: promptText, message: "Name?"}
+	if r := p.answerFrom(in, &out); r.value != "bob" {
+		t.Errorf("unexpected text %+v", r)
+	}
+	if r := p.answerFrom(in, &out); !r.canceled {
+		t.Error("prompt not canceled at the end of the input")
+	}
+	if out.String() != "Sure? (y/n) Sure? (y/n) Which?\n1. a\n2. b\n? Name? Name? \n" {
+		t.Errorf("unexpected prompts %q", out.String())
+	}
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell"
+)
+
+//Narrowest screen, in columns, that shows the split pane layout
+var SplitPaneMinWidth = 80
+
+//gets the column dividing the command list from the preview pane, 0 when the menu is not split
+func (m *Menu) splitColumn() int {
+	if !m.SplitPane {
+		return 0
+	}
+	w, _ := m.p.Screen().Size()
+	if w < SplitPaneMinWidth {
+		return 0
+	}
+	return w * 2 / 5
+}
+
+//draws the highlighted command details to the right of the split column, between rows top and limit
+func (m *Menu) drawPreview(split, top, limit int) {
+	s := m.p.Screen()
+	for y := top; y < limit; y++ {
+		s.SetContent(split, y, tcell.RuneVLine, nil, m.p.style.Default)
+	}
+	m.p.Pane(split+1, 0)
+	m.p.Cursor = top
+	for _, l := range m.previewLines(m.CurrentCommand()) {
+		if m.p.Cursor >= limit {
+			break
+		}
+		switch {
+		case l.h1:
+			m.p.PutH1(l.text, false)
+		case l.disable:
+			m.p.PutlnDisable(l.text)
+		default:
+			m.p.Putln(l.text, false)
+		}
+	}
+}
+
+//a line in the preview pane
+type previewLine struct {
+	text    string
+	h1      bool
+	disable bool
+}
+
+//gets the preview of a command: title, description, arguments, command line and last status
+func (m *Menu) previewLines(c *Command) []previewLine {
+	lines := []previewLine{{text: c.Title, h1: true}}
+	if c.Description != "" {
+		lines = append(lines, previewLine{text: c.Description})
+	}
+	if len(c.Args) > 0 {
+		lines = append(lines, previewLine{}, previewLine{text: m.ArgsText})
+		for _, a := range c.Args {
+			lines = append(lines, previewLine{text: "  " + a.Name + ": " + a.Title})
+			if a.Description != "" {
+				lines = append(lines, previewLine{text: "    " + a.Description, disable: true})
+			}
+		}
+	}
+	if c.Cli != "" {
+		lines = append(lines, previewLine{}, previewLine{text: m.CliText}, previewLine{text: "  " + c.commandLine(), disable: true})
+	}
+	if c.Status == "" && !c.ran {
+		return lines
+	}
+	lines = append(lines, previewLine{}, previewLine{text: m.StatusText})
+	if c.Status != "" {
+		lines = append(lines, previewLine{text: "  " + c.Status})
+	}
+	if c.ran && c.Error != nil {
+		lines = append(lines, previewLine{text: "  " + c.Fail + " Error ocurred:" + c.Error.Error()})
+	} else if c.ran {
+		lines = append(lines, previewLine{text: "  Success! " + c.Success})
+	}
+	return lines
+}
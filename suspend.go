@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+//runs a Suspend command with the terminal of the menu, which is drawn again once it exits
+//failures show their error, as the output went to the terminal
+func (m *Menu) runSuspended(c *Command) {
+	c.resetEvents()
+	m.resultScroll = 0
+	m.command = c
+	m.p.Suspend()
+
+	c.setEnv()
+	cliArray := strings.Split(c.Cli, " ")
+	cmd := exec.Command(cliArray[0], c.cliArgs()...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Error = cmd.Run()
+	c.ran = true
+	if c.WaitKey {
+		fmt.Fprint(os.Stdout, "\n"+m.WaitKeyText)
+		readKey(os.Stdin)
+	}
+
+	if err := m.p.Resume(); err != nil {
+		m.err = err
+		return
+	}
+	if m.Mouse {
+		m.p.Screen().EnableMouse()
+	}
+	if c.Error != nil {
+		m.ShowResult(c)
+		return
+	}
+	m.Show()
+}
+
+//reads up to a new line or the end of f
+func readLine(f *os.File) {
+	b := make([]byte, 1)
+	for {
+		if n, err := f.Read(b); err != nil || n == 0 || b[0] == '\n' {
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// +build linux
+
+package tui
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+//waits for a single key press, turning off line buffering on terminals
+func readKey(f *os.File) {
+	var old syscall.Termios
+	if ioctl(f, syscall.TCGETS, uintptr(unsafe.Pointer(&old))) != nil {
+		readLine(f)
+		return
+	}
+	raw := old
+	raw.Lflag &^= syscall.ICANON | syscall.ECHO
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+	if ioctl(f, syscall.TCSETS, uintptr(unsafe.Pointer(&raw))) != nil {
+		readLine(f)
+		return
+	}
+	defer ioctl(f, syscall.TCSETS, uintptr(unsafe.Pointer(&old)))
+	f.Read(make([]byte, 1))
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package tui
+
+import (
+	"os"
+)
+
+//waits for a new line, terminals are left in line mode
+func readKey(f *os.File) {
+	readLine(f)
+}
@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestSuspend(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	m, err := NewMenuWithScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Quit()
+	m.Title = "Test"
+	m.Commands = []Command{
+		{Title: "First", Cli: "true", Suspend: true},
+		{Title: "Second", Cli: "false", Suspend: true},
+	}
+	m.activate()
+	if m.err != nil || m.view != viewMenu || m.p.suspended {
+		t.Fatalf("menu not back after the command: %v", m.err)
+	}
+	if l := screenLine(s, 2); l != "  First" {
+		t.Errorf("menu not drawn again: %q", l)
+	}
+
+	m.Next()
+	m.activate()
+	if m.view != viewResult || m.Commands[1].Error == nil {
+		t.Error("failure not shown")
+	}
+	if l := screenLine(s, 2); !strings.Contains(l, "exit status 1") {
+		t.Errorf("unexpected result %q", l)
+	}
+}
@@ -0,0 +1,453 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+	"github.com/mattn/go-runewidth"
+)
+
+//Longest escape sequence kept while waiting for its end, longer ones are dropped
+const maxSequence = 4096
+
+//a cell of the terminal screen, r is 0 for the second column of a wide rune
+type termCell struct {
+	r     rune
+	style tcell.Style
+}
+
+//VT100/xterm emulator for interactive commands, it keeps the screen the command draws
+//with escape sequences, which the menu copies to its own screen
+type terminal struct {
+	w, h      int
+	cells     [][]termCell
+	alt       [][]termCell //main screen while the alternate one is shown
+	x, y      int
+	wrapNext  bool //the last column was written, the next rune goes to the next line
+	top       int  //first row of the scroll region
+	bottom    int  //last row of the scroll region
+	saveX     int
+	saveY     int
+	saveStyle tcell.Style
+	ansi      *ansiParser //current style
+	hidden    bool        //cursor is hidden
+	appCursor bool        //cursor keys send application sequences
+	pending   []byte      //incomplete sequence or rune at the end of the last write
+	replies   []byte      //answers to queries, to be written back to the command
+}
+
+func newTerminal(w, h int, ansi *ansiParser) *terminal {
+	t := &terminal{ansi: ansi}
+	t.Resize(w, h)
+	return t
+}
+
+//changes the size of the screen, keeping the top left contents
+func (t *terminal) Resize(w, h int) {
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	t.w, t.h = w, h
+	t.cells = t.resized(t.cells)
+	if t.alt != nil {
+		t.alt = t.resized(t.alt)
+	}
+	t.top, t.bottom = 0, h-1
+	t.moveTo(t.x, t.y)
+}
+
+//gets the rune and style at a cell
+func (t *terminal) Cell(x, y int) (rune, tcell.Style) {
+	c := t.cells[y][x]
+	return c.r, c.style
+}
+
+//gets the cursor position and whether it is shown
+func (t *terminal) Cursor() (int, int, bool) {
+	return t.x, t.y, !t.hidden
+}
+
+//takes the answers to queries such as the cursor position, to be sent to the command
+func (t *terminal) Replies() []byte {
+	r := t.replies
+	t.replies = nil
+	return r
+}
+
+//updates the screen with output from the command
+func (t *terminal) Write(data []byte) {
+	data = append(t.pending, data...)
+	t.pending = nil
+	for i := 0; i < len(data); {
+		n := t.step(data[i:])
+		if n == 0 {
+			if len(data)-i < maxSequence {
+				t.pending = append([]byte{}, data[i:]...)
+				return
+			}
+			n = 1
+		}
+		i += n
+	}
+}
+
+//handles the control, escape sequence or rune at the start of b
+//returns the bytes used, 0 if b ends before it is complete
+func (t *terminal) step(b []byte) int {
+	switch c := b[0]; {
+	case c == 0x1b:
+		return t.escape(b)
+	case c == '\r':
+		t.moveTo(0, t.y)
+	case c == '\n' || c == 0x0b || c == 0x0c:
+		t.lineFeed()
+	case c == '\b':
+		t.moveTo(t.x-1, t.y)
+	case c == '\t':
+		t.moveTo((t.x/TabWidth+1)*TabWidth, t.y)
+	case c < 0x20 || c == 0x7f:
+	default:
+		if !utf8.FullRune(b) {
+			return 0
+		}
+		r, n := utf8.DecodeRune(b)
+		t.put(r)
+		return n
+	}
+	return 1
+}
+
+//handles an escape sequence, returns the bytes used or 0 if incomplete
+func (t *terminal) escape(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+	switch b[1] {
+	case '[':
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				t.csi(string(b[2:i]), b[i])
+				return i + 1
+			}
+		}
+		return 0
+	case ']', 'P', 'X', '^', '_':
+		//strings end with BEL or ESC \
+		for i := 2; i < len(b); i++ {
+			if b[i] == 0x07 {
+				return i + 1
+			}
+			if b[i] == 0x1b && i+1 < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return 0
+	case '(', ')', '*', '+', '#', '%':
+		if len(b) < 3 {
+			return 0
+		}
+		return 3
+	case '7':
+		t.save()
+	case '8':
+		t.restore()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.moveTo(0, t.y)
+		t.lineFeed()
+	case 'M':
+		if t.y == t.top {
+			t.scrollDown(1)
+		} else {
+			t.moveTo(t.x, t.y-1)
+		}
+	case 'c':
+		t.reset()
+	}
+	return 2
+}
+
+//handles a control sequence with its parameters and final byte
+func (t *terminal) csi(params string, final byte) {
+	private := strings.HasPrefix(params, "?")
+	if private || strings.HasPrefix(params, ">") {
+		params = params[1:]
+	}
+	nums := []int{}
+	for _, p := range strings.Split(params, ";") {
+		n, _ := strconv.Atoi(p)
+		nums = append(nums, n)
+	}
+	//arg gets a parameter, def when it is missing or 0
+	arg := func(i, def int) int {
+		if i < len(nums) && nums[i] > 0 {
+			return nums[i]
+		}
+		return def
+	}
+	switch final {
+	case 'A':
+		t.moveTo(t.x, t.y-arg(0, 1))
+	case 'B', 'e':
+		t.moveTo(t.x, t.y+arg(0, 1))
+	case 'C', 'a':
+		t.moveTo(t.x+arg(0, 1), t.y)
+	case 'D':
+		t.moveTo(t.x-arg(0, 1), t.y)
+	case 'E':
+		t.moveTo(0, t.y+arg(0, 1))
+	case 'F':
+		t.moveTo(0, t.y-arg(0, 1))
+	case 'G', '`':
+		t.moveTo(arg(0, 1)-1, t.y)
+	case 'd':
+		t.moveTo(t.x, arg(0, 1)-1)
+	case 'H', 'f':
+		t.moveTo(arg(1, 1)-1, arg(0, 1)-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			t.clear(t.x, t.y, t.w-1, t.h-1)
+		case 1:
+			t.clear(0, 0, t.x, t.y)
+		default:
+			t.clear(0, 0, t.w-1, t.h-1)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			t.clear(t.x, t.y, t.w-1, t.y)
+		case 1:
+			t.clear(0, t.y, t.x, t.y)
+		default:
+			t.clear(0, t.y, t.w-1, t.y)
+		}
+	case 'L':
+		if t.y >= t.top && t.y <= t.bottom {
+			top := t.top
+			t.top = t.y
+			t.scrollDown(arg(0, 1))
+			t.top = top
+		}
+	case 'M':
+		if t.y >= t.top && t.y <= t.bottom {
+			top := t.top
+			t.top = t.y
+			t.scrollUp(arg(0, 1))
+			t.top = top
+		}
+	case '@':
+		row := t.cells[t.y]
+		n := min(arg(0, 1), t.w-t.x)
+		copy(row[t.x+n:], row[t.x:])
+		t.clear(t.x, t.y, t.x+n-1, t.y)
+	case 'P':
+		row := t.cells[t.y]
+		n := min(arg(0, 1), t.w-t.x)
+		copy(row[t.x:], row[t.x+n:])
+		t.clear(t.w-n, t.y, t.w-1, t.y)
+	case 'X':
+		t.clear(t.x, t.y, min(t.x+arg(0, 1), t.w)-1, t.y)
+	case 'S':
+		t.scrollUp(arg(0, 1))
+	case 'T':
+		t.scrollDown(arg(0, 1))
+	case 'm':
+		if !private {
+			t.ansi.sgr(params)
+		}
+	case 'r':
+		top, bottom := arg(0, 1)-1, arg(1, t.h)-1
+		if top < bottom && bottom < t.h {
+			t.top, t.bottom = top, bottom
+			t.moveTo(0, 0)
+		}
+	case 's':
+		t.save()
+	case 'u':
+		t.restore()
+	case 'h', 'l':
+		if private {
+			t.mode(nums, final == 'h')
+		}
+	case 'n':
+		switch arg(0, 0) {
+		case 5:
+			t.replies = append(t.replies, "\x1b[0n"...)
+		case 6:
+			t.replies = append(t.replies, fmt.Sprintf("\x1b[%d;%dR", t.y+1, t.x+1)...)
+		}
+	case 'c':
+		if !private {
+			t.replies = append(t.replies, "\x1b[?1;2c"...)
+		}
+	}
+}
+
+//sets or resets DEC private modes
+func (t *terminal) mode(modes []int, set bool) {
+	for _, m := range modes {
+		switch m {
+		case 1:
+			t.appCursor = set
+		case 25:
+			t.hidden = !set
+		case 47, 1047, 1049:
+			if set && t.alt == nil {
+				if m == 1049 {
+					t.save()
+				}
+				t.alt = t.cells
+				t.cells = t.blankRows(t.h)
+			} else if !set && t.alt != nil {
+				t.cells = t.alt
+				t.alt = nil
+				if m == 1049 {
+					t.restore()
+				}
+			}
+		}
+	}
+}
+
+//writes a rune at the cursor and moves it
+func (t *terminal) put(r rune) {
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		return
+	}
+	if t.wrapNext || t.x+w > t.w {
+		t.moveTo(0, t.y)
+		t.lineFeed()
+	}
+	t.cells[t.y][t.x] = termCell{r, t.ansi.style}
+	if w == 2 && t.x+1 < t.w {
+		t.cells[t.y][t.x+1] = termCell{0, t.ansi.style}
+	}
+	if t.x+w >= t.w {
+		t.wrapNext = true
+		return
+	}
+	t.x += w
+}
+
+//moves the cursor down, scrolling at the bottom of the scroll region
+func (t *terminal) lineFeed() {
+	if t.y == t.bottom {
+		t.scrollUp(1)
+		return
+	}
+	t.moveTo(t.x, t.y+1)
+}
+
+//moves the cursor, keeping it in the screen
+func (t *terminal) moveTo(x, y int) {
+	t.x = max(0, min(x, t.w-1))
+	t.y = max(0, min(y, t.h-1))
+	t.wrapNext = false
+}
+
+//scrolls the scroll region up n lines
+func (t *terminal) scrollUp(n int) {
+	n = min(n, t.bottom-t.top+1)
+	copy(t.cells[t.top:t.bottom+1], t.cells[t.top+n:t.bottom+1])
+	for y := t.bottom - n + 1; y <= t.bottom; y++ {
+		t.cells[y] = t.blankRow()
+	}
+}
+
+//scrolls the scroll region down n lines
+func (t *terminal) scrollDown(n int) {
+	n = min(n, t.bottom-t.top+1)
+	copy(t.cells[t.top+n:t.bottom+1], t.cells[t.top:t.bottom+1-n])
+	for y := t.top; y < t.top+n; y++ {
+		t.cells[y] = t.blankRow()
+	}
+}
+
+//blanks the cells from x0, y0 to x1, y1 both included, in reading order
+func (t *terminal) clear(x0, y0, x1, y1 int) {
+	for y := y0; y <= y1; y++ {
+		from, to := 0, t.w-1
+		if y == y0 {
+			from = x0
+		}
+		if y == y1 {
+			to = x1
+		}
+		for x := from; x <= to; x++ {
+			t.cells[y][x] = t.blank()
+		}
+	}
+}
+
+func (t *terminal) save() {
+	t.saveX, t.saveY, t.saveStyle = t.x, t.y, t.ansi.style
+}
+
+func (t *terminal) restore() {
+	t.moveTo(t.saveX, t.saveY)
+	t.ansi.style = t.saveStyle
+}
+
+//goes back to the initial state
+func (t *terminal) reset() {
+	t.ansi.style = t.ansi.base
+	t.alt = nil
+	t.hidden, t.appCursor = false, false
+	t.cells = t.blankRows(t.h)
+	t.top, t.bottom = 0, t.h-1
+	t.moveTo(0, 0)
+}
+
+//gets an empty cell, with the current background
+func (t *terminal) blank() termCell {
+	_, bg, _ := t.ansi.style.Decompose()
+	return termCell{' ', t.ansi.base.Background(bg)}
+}
+
+func (t *terminal) blankRow() []termCell {
+	row := make([]termCell, t.w)
+	for x := range row {
+		row[x] = t.blank()
+	}
+	return row
+}
+
+//gets a copy of rows in the current size
+func (t *terminal) resized(rows [][]termCell) [][]termCell {
+	r := t.blankRows(t.h)
+	for y := 0; y < t.h && y < len(rows); y++ {
+		copy(r[y], rows[y])
+	}
+	return r
+}
+
+func (t *terminal) blankRows(h int) [][]termCell {
+	rows := make([][]termCell, h)
+	for y := range rows {
+		rows[y] = t.blankRow()
+	}
+	return rows
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func termRow(t *terminal, y int) string {
+	row := []rune{}
+	for x := 0; x < t.w; x++ {
+		if r, _ := t.Cell(x, y); r != 0 {
+			row = append(row, r)
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+func checkTerm(t *testing.T, term *terminal, rows ...string) {
+	for y, want := range rows {
+		if got := termRow(term, y); got != want {
+			t.Errorf("row %d: got %q want %q", y, got, want)
+		}
+	}
+}
+
+func TestTerminalText(t *testing.T) {
+	term := newTerminal(10, 3, newANSIParser(tcell.StyleDefault))
+	term.Write([]byte("hello\r\nworld\tx"))
+	checkTerm(t, term, "hello", "world   x")
+	term.Write([]byte("\x1b[1;2H\x1b[K\x1b[3;3HX\x1b[2;1H\x1b[2P"))
+	checkTerm(t, term, "h", "rld   x", "  X")
+	if x, y, ok := term.Cursor(); x != 0 || y != 1 || !ok {
+		t.Errorf("cursor at %d,%d", x, y)
+	}
+}
+
+func TestTerminalWrapAndScroll(t *testing.T) {
+	term := newTerminal(3, 2, newANSIParser(tcell.StyleDefault))
+	term.Write([]byte("abcdefgh"))
+	checkTerm(t, term, "def", "gh")
+	term.Write([]byte("\x1b[H\x1bM1"))
+	checkTerm(t, term, "1", "def")
+}
+
+func TestTerminalScrollRegion(t *testing.T) {
+	term := newTerminal(4, 4, newANSIParser(tcell.StyleDefault))
+	term.Write([]byte("a\r\nb\r\nc\r\nd\x1b[2;3r\x1b[3;1H\n\nx\x1b[2;1H\x1b[L"))
+	checkTerm(t, term, "a", "", "", "d")
+	if termRow(term, 2) != "" || termRow(term, 1) != "" {
+		t.Error("lines not inserted in the scroll region")
+	}
+}
+
+func TestTerminalSplitWrites(t *testing.T) {
+	term := newTerminal(10, 2, newANSIParser(tcell.StyleDefault))
+	for _, b := range []string{"\x1b[3", "1mr", "\xc3", "\xa9\x1b]0;ti", "tle\x07!"} {
+		term.Write([]byte(b))
+	}
+	checkTerm(t, term, "ré!")
+	if _, style := term.Cell(0, 0); style != tcell.StyleDefault.Foreground(tcell.ColorMaroon) {
+		t.Error("color not applied")
+	}
+}
+
+func TestTerminalAltScreen(t *testing.T) {
+	term := newTerminal(10, 2, newANSIParser(tcell.StyleDefault))
+	term.Write([]byte("main\x1b[?1049h\x1b[HALT\x1b[?25l"))
+	checkTerm(t, term, "ALT")
+	if _, _, ok := term.Cursor(); ok {
+		t.Error("cursor not hidden")
+	}
+	term.Write([]byte("\x1b[?1049l"))
+	checkTerm(t, term, "main")
+	if x, _, _ := term.Cursor(); x != 4 {
+		t.Errorf("cursor not restored: %d", x)
+	}
+}
+
+func TestTerminalReplies(t *testing.T) {
+	term := newTerminal(10, 5, newANSIParser(tcell.StyleDefault))
+	term.Write([]byte("\x1b[3;4H\x1b[6n"))
+	if r := string(term.Replies()); r != "\x1b[3;4R" {
+		t.Errorf("unexpected reply %q", r)
+	}
+	if r := term.Replies(); len(r) != 0 {
+		t.Error("replies not taken")
+	}
+}
+
+func TestKeyBytes(t *testing.T) {
+	for _, k := range []struct {
+		ev        *tcell.EventKey
+		appCursor bool
+		want      string
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'é', tcell.ModNone), false, "é"},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModAlt), false, "\x1bx"},
+		{tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModCtrl), false, "\x03"},
+		{tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), false, "\r"},
+		{tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), false, "\x1b[A"},
+		{tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone), true, "\x1bOA"},
+		{tcell.NewEventKey(tcell.KeyF5, 0, tcell.ModNone), true, "\x1b[15~"},
+	} {
+		if got := string(keyBytes(k.ev, k.appCursor)); got != k.want {
+			t.Errorf("got %q want %q", got, k.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+//Built-in styles by name, to be used from theme files and menu files
+var Themes = map[string]func() *Style{
+	"classic":       DefaultStyle,
+	"dark":          DarkStyle,
+	"light":         LightStyle,
+	"high-contrast": HighContrastStyle,
+}
+
+//returns a style with light text on black
+func DarkStyle() *Style {
+	bg := tcell.ColorBlack
+	return &Style{
+		Hightlight: tcell.StyleDefault.Background(tcell.Color67).Foreground(tcell.ColorWhite).Bold(true),
+		Default:    tcell.StyleDefault.Foreground(tcell.Color252).Background(bg),
+		Menu:       tcell.StyleDefault.Background(tcell.Color236).Foreground(tcell.Color252),
+		H1:         tcell.StyleDefault.Foreground(tcell.Color110).Background(bg).Bold(true),
+		Input:      tcell.StyleDefault.Foreground(tcell.Color150).Background(bg),
+		Disable:    tcell.StyleDefault.Foreground(tcell.Color243).Background(bg),
+		Indent:     2,
+	}
+}
+
+//returns a style with dark text on white
+func LightStyle() *Style {
+	bg := tcell.Color231
+	return &Style{
+		Hightlight: tcell.StyleDefault.Background(tcell.Color25).Foreground(tcell.Color231).Bold(true),
+		Default:    tcell.StyleDefault.Foreground(tcell.Color235).Background(bg),
+		Menu:       tcell.StyleDefault.Background(tcell.Color252).Foreground(tcell.Color235),
+		H1:         tcell.StyleDefault.Foreground(tcell.Color25).Background(bg).Bold(true),
+		Input:      tcell.StyleDefault.Foreground(tcell.Color28).Background(bg),
+		Disable:    tcell.StyleDefault.Foreground(tcell.Color246).Background(bg),
+		Indent:     2,
+	}
+}
+
+//returns a style with pure black and white and yellow input, for low vision users
+func HighContrastStyle() *Style {
+	bg := tcell.ColorBlack
+	return &Style{
+		Hightlight: tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(tcell.ColorBlack).Bold(true),
+		Default:    tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(bg).Bold(true),
+		Menu:       tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlack).Bold(true),
+		H1:         tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(bg).Bold(true).Underline(true),
+		Input:      tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(bg).Bold(true),
+		Disable:    tcell.StyleDefault.Foreground(tcell.ColorSilver).Background(bg),
+		Indent:     2,
+	}
+}
+
+//Style of an element in a theme file
+//Colors are W3C names ("navy"), hex values ("#00005f") or palette numbers ("17")
+type ThemeStyle struct {
+	Fg        string
+	Bg        string
+	Bold      bool
+	Underline bool
+	Reverse   bool
+	Dim       bool
+}
+
+//Theme file, a JSON object such as:
+// {
+//   "base": "dark",
+//   "indent": 4,
+//   "highlight": {"fg": "black", "bg": "#ffaf00", "bold": true}
+// }
+//Base is the name of a built-in style, "classic" if empty, elements left out keep its values
+//Setting Brand instead derives the base style from a color, see BrandStyle
+type Theme struct {
+	Base      string
+	Brand     string
+	Indent    *int
+	Default   *ThemeStyle
+	Highlight *ThemeStyle
+	Menu      *ThemeStyle
+	H1        *ThemeStyle
+	Input     *ThemeStyle
+	Disable   *ThemeStyle
+}
+
+//Returns a built-in style by name, a brand style for a "#rrggbb" color, or the style in a theme file
+func LoadTheme(nameOrPath string) (*Style, error) {
+	if f, ok := Themes[nameOrPath]; ok {
+		return f(), nil
+	}
+	if strings.HasPrefix(nameOrPath, "#") {
+		return BrandStyle(nameOrPath)
+	}
+	b, err := ioutil.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, err
+	}
+	t := &Theme{}
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, fmt.Errorf("%s: %v", nameOrPath, err)
+	}
+	return t.Style()
+}
+
+//Returns the style described by the theme
+func (t *Theme) Style() (*Style, error) {
+	s, err := t.base()
+	if err != nil {
+		return nil, err
+	}
+	if t.Indent != nil {
+		s.Indent = *t.Indent
+	}
+	for _, e := range []struct {
+		ts    *ThemeStyle
+		style *tcell.Style
+	}{
+		{t.Default, &s.Default},
+		{t.Highlight, &s.Hightlight},
+		{t.Menu, &s.Menu},
+		{t.H1, &s.H1},
+		{t.Input, &s.Input},
+		{t.Disable, &s.Disable},
+	} {
+		if e.ts == nil {
+			continue
+		}
+		style, err := e.ts.style()
+		if err != nil {
+			return nil, err
+		}
+		*e.style = style
+	}
+	return s, nil
+}
+
+//returns the style the theme starts from
+func (t *Theme) base() (*Style, error) {
+	if t.Brand != "" {
+		return BrandStyle(t.Brand)
+	}
+	base := t.Base
+	if base == "" {
+		base = "classic"
+	}
+	f, ok := Themes[base]
+	if !ok {
+		return nil, fmt.Errorf("unknown base theme %q", base)
+	}
+	return f(), nil
+}
+
+//converts to a tcell style
+func (ts *ThemeStyle) style() (tcell.Style, error) {
+	fg, err := parseColor(ts.Fg)
+	if err != nil {
+		return tcell.StyleDefault, err
+	}
+	bg, err := parseColor(ts.Bg)
+	if err != nil {
+		return tcell.StyleDefault, err
+	}
+	return tcell.StyleDefault.Foreground(fg).Background(bg).
+		Bold(ts.Bold).Underline(ts.Underline).Reverse(ts.Reverse).Dim(ts.Dim), nil
+}
+
+//parses a color name, hex value or palette number, empty is the terminal default
+func parseColor(s string) (tcell.Color, error) {
+	if s == "" || s == "default" {
+		return tcell.ColorDefault, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n < 256 {
+		return tcell.Color(n), nil
+	}
+	if c := tcell.GetColor(s); c != tcell.ColorDefault {
+		return c, nil
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color %q", s)
+}
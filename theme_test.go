@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+func TestLoadThemePreset(t *testing.T) {
+	for name := range Themes {
+		if s, err := LoadTheme(name); err != nil || s == nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	s, err := LoadTheme("./sampleapp/theme.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Indent != 4 {
+		t.Errorf("indent not set: %d", s.Indent)
+	}
+	fg, bg, attr := s.Hightlight.Decompose()
+	if fg != tcell.ColorBlack || bg != tcell.NewHexColor(0xffaf00) || attr&tcell.AttrBold == 0 {
+		t.Errorf("unexpected highlight %v %v %v", fg, bg, attr)
+	}
+	if fg, _, _ := s.Input.Decompose(); fg != tcell.Color214 {
+		t.Errorf("unexpected input color %v", fg)
+	}
+	if s.Default != DarkStyle().Default {
+		t.Error("default not taken from base theme")
+	}
+}
+
+func TestThemeErrors(t *testing.T) {
+	for _, js := range []string{
+		`{"base": "nope"}`,
+		`{"default": {"fg": "notacolor"}}`,
+		`{"menu": {"bg": "256"}}`,
+	} {
+		th := &Theme{}
+		if err := json.Unmarshal([]byte(js), th); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := th.Style(); err == nil {
+			t.Errorf("expected error for %s", js)
+		}
+	}
+	if _, err := LoadTheme("./sampleapp/nofile.json"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestPrintingThemeBackground(t *testing.T) {
+	s := tcell.NewSimulationScreen("")
+	if _, err := NewPrintingScreen(s, LightStyle()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Fini()
+	if _, _, style, _ := s.GetContent(0, 0); style != LightStyle().Default {
+		t.Error("screen does not use the theme background")
+	}
+}
+
+func TestBrandStyle(t *testing.T) {
+	for _, hex := range []string{"#e95420", "#ffff00", "#000000", "#ffffff", "#0000ff"} {
+		s, err := BrandStyle(hex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, st := range s.styles() {
+			fg, bg, _ := st.Decompose()
+			min := textContrast
+			if st == &s.Disable {
+				min = disableContrast
+			}
+			if r := contrastRatio(colorfulColor(fg), colorfulColor(bg)); r < min {
+				t.Errorf("%s: style %d contrast %.2f", hex, i, r)
+			}
+		}
+	}
+	if _, err := BrandStyle("orange"); err == nil {
+		t.Error("expected error for a color name")
+	}
+	if s, err := LoadTheme("#e95420"); err != nil || s.Hightlight == DefaultStyle().Hightlight {
+		t.Errorf("brand color not loaded: %v", err)
+	}
+}
+
+func colorfulColor(c tcell.Color) colorful.Color {
+	r, g, b := c.RGB()
+	return colorful.Color{R: float64(r) / 255, G: float64(g) / 255, B: float64(b) / 255}
+}
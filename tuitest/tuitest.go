@@ -0,0 +1,256 @@
+// Package tuitest drives a tui.Menu on a tcell simulation screen, so menu flows can be
+// covered by regression tests:
+//
+//  h := tuitest.New(t, tui.DefaultStyle())
+//  h.Menu.Commands = cmds
+//  h.Start()
+//  h.Press(tcell.KeyDown, tcell.KeyEnter)
+//  h.Type("foo")
+//  h.Press(tcell.KeyEnter)
+//  h.Golden("run-foo")
+//
+// Every key sent waits for the menu to process it, including any command it runs.
+// Golden files live in testdata and are rewritten when tests run with -update
+package tuitest
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/vtuson/tui"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+//Default time to wait for the menu to process input
+const DefaultTimeout = 10 * time.Second
+
+//simulation screen that tracks when the menu is waiting for events
+type screen struct {
+	tcell.SimulationScreen
+	mu       sync.Mutex
+	posted   int
+	received int
+	polling  bool
+}
+
+func (s *screen) PollEvent() tcell.Event {
+	s.mu.Lock()
+	s.polling = true
+	s.mu.Unlock()
+	ev := s.SimulationScreen.PollEvent()
+	s.mu.Lock()
+	s.polling = false
+	if ev != nil {
+		s.received++
+	}
+	s.mu.Unlock()
+	return ev
+}
+
+func (s *screen) PostEvent(ev tcell.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.SimulationScreen.PostEvent(ev)
+	if err == nil {
+		s.posted++
+	}
+	return err
+}
+
+func (s *screen) PostEventWait(ev tcell.Event) {
+	s.mu.Lock()
+	s.posted++
+	s.mu.Unlock()
+	s.SimulationScreen.PostEventWait(ev)
+}
+
+//true when every posted event was handled and the menu waits for more
+//the count of received events tells if the menu handled anything in between two calls
+func (s *screen) idle() (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.polling && s.received == s.posted, s.received
+}
+
+//Test harness running a Menu on a simulation screen
+type Harness struct {
+	Menu    *tui.Menu
+	Screen  tcell.SimulationScreen
+	Timeout time.Duration
+	t       testing.TB
+	s       *screen
+	done    chan struct{}
+	err     error
+}
+
+//Returns a harness with a menu on an 80x25 simulation screen
+func New(t testing.TB, style *tui.Style) *Harness {
+	s := &screen{SimulationScreen: tcell.NewSimulationScreen("")}
+	m, err := tui.NewMenuWithScreen(s, style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Harness{
+		Menu:    m,
+		Screen:  s.SimulationScreen,
+		Timeout: DefaultTimeout,
+		t:       t,
+		s:       s,
+		done:    make(chan struct{}),
+	}
+}
+
+//Runs the menu in the background
+func (h *Harness) Start() {
+	go func() {
+		h.err = h.Menu.Run()
+		close(h.done)
+	}()
+	h.Wait()
+}
+
+//Returns the error returned by Menu.Run once the user exited the menu
+func (h *Harness) Err() error {
+	if !h.Exited() {
+		return nil
+	}
+	return h.err
+}
+
+//Waits until the menu processed all input and finished any command it was running
+func (h *Harness) Wait() {
+	deadline := time.Now().Add(h.Timeout)
+	for !h.idle() {
+		select {
+		case <-h.done:
+			return
+		default:
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("menu still busy after %v, screen:\n%s", h.Timeout, h.Text())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+//true when the menu is waiting for events with no command running
+func (h *Harness) idle() bool {
+	idle, n := h.s.idle()
+	if !idle {
+		return false
+	}
+	busy := h.Menu.Busy()
+	//busy is only trusted if the menu handled no event while reading it
+	idle, m := h.s.idle()
+	return idle && n == m && !busy
+}
+
+//Reports whether the user has exited the menu
+func (h *Harness) Exited() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
+
+//Sends keys to the menu, one at a time
+func (h *Harness) Press(keys ...tcell.Key) {
+	for _, k := range keys {
+		h.send(tcell.NewEventKey(k, 0, tcell.ModNone))
+	}
+}
+
+//Types text into the menu, one rune at a time
+func (h *Harness) Type(text string) {
+	for _, r := range text {
+		h.send(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+//Sends a mouse event at a screen position, use tcell.ButtonNone to release buttons
+func (h *Harness) Mouse(x, y int, buttons tcell.ButtonMask) {
+	h.send(tcell.NewEventMouse(x, y, buttons, tcell.ModNone))
+}
+
+//Clicks at a screen position
+func (h *Harness) Click(x, y int) {
+	h.Mouse(x, y, tcell.Button1)
+	if !h.Exited() {
+		h.Mouse(x, y, tcell.ButtonNone)
+	}
+}
+
+//Resizes the screen, the simulation screen can only shrink below its initial 80x25
+func (h *Harness) Resize(width, height int) {
+	if w, ht := h.Screen.Size(); w == width && ht == height {
+		return
+	}
+	h.Screen.SetSize(width, height)
+	//the simulation screen posts the resize event itself when showing the new size
+	h.s.mu.Lock()
+	h.s.posted++
+	h.s.mu.Unlock()
+	h.Screen.Show()
+	h.Wait()
+}
+
+//posts an event and waits for the menu to handle it
+func (h *Harness) send(ev tcell.Event) {
+	if h.Exited() {
+		h.t.Fatal("menu has exited")
+	}
+	h.s.PostEventWait(ev)
+	h.Wait()
+}
+
+//Returns the screen shown to the user as plain text, without trailing spaces
+func (h *Harness) Text() string {
+	cells, w, ht := h.Screen.GetContents()
+	lines := []string{}
+	for y := 0; y < ht; y++ {
+		line := []rune{}
+		for _, c := range cells[y*w : (y+1)*w] {
+			if len(c.Runes) == 0 {
+				continue
+			}
+			line = append(line, c.Runes...)
+		}
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+//Compares the screen with testdata/name.golden, writing the file instead when -update is set
+func (h *Harness) Golden(name string) {
+	path := filepath.Join("testdata", name+".golden")
+	got := h.Text()
+	if *update {
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("%v, run with -update to create it", err)
+	}
+	if got != string(want) {
+		h.t.Errorf("screen does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+//Releases the screen if the menu is still running
+func (h *Harness) Close() {
+	if !h.Exited() {
+		h.Menu.Quit()
+	}
+}
@@ -0,0 +1,267 @@
+package tuitest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+	"github.com/vtuson/tui"
+)
+
+func newTestHarness(t *testing.T) *Harness {
+	h := newTestMenu(t)
+	h.Start()
+	return h
+}
+
+//creates a harness for the test menu without starting it
+func newTestMenu(t *testing.T) *Harness {
+	h := New(t, tui.DefaultStyle())
+	h.Menu.Title = "Test"
+	h.Menu.Description = "Test app"
+	h.Menu.Commands = []tui.Command{
+		tui.Command{
+			Title:       "No Args",
+			Cli:         "echo hello!",
+			Description: "just being polite",
+			Success:     "Yey it works",
+			PrintOut:    true,
+		},
+		tui.Command{
+			Title:       "Args CLI",
+			Cli:         "echo",
+			Description: "test of running a tui.Command with arguments",
+			PrintOut:    true,
+			Args: []tui.Argument{
+				tui.Argument{
+					Title: "Please say hi",
+					Name:  "say",
+				},
+			},
+		},
+	}
+	return h
+}
+
+func TestMenuFlow(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+	h.Golden("menu")
+
+	h.Press(tcell.KeyEnter)
+	h.Golden("no-args-result")
+
+	h.Press(tcell.KeyEscape, tcell.KeyDown, tcell.KeyEnter)
+	h.Type("hi")
+	h.Golden("args-prompt")
+
+	h.Press(tcell.KeyEnter)
+	h.Golden("args-result")
+
+	h.Press(tcell.KeyEscape, tcell.KeyEscape)
+	if !h.Exited() || h.Err() != nil {
+		t.Errorf("menu did not exit cleanly: %v", h.Err())
+	}
+}
+
+func TestSplitPane(t *testing.T) {
+	h := newTestMenu(t)
+	defer h.Close()
+	h.Menu.SplitPane = true
+	h.Start()
+	h.Press(tcell.KeyDown)
+	h.Golden("split-pane")
+
+	h.Press(tcell.KeyEnter)
+	h.Type("hi")
+	h.Press(tcell.KeyEnter, tcell.KeyEscape)
+	h.Golden("split-pane-ran")
+
+	h.Resize(60, 20)
+	h.Golden("split-pane-narrow")
+}
+
+func TestResize(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+	h.Resize(40, 10)
+	h.Press(tcell.KeyDown)
+	h.Golden("resized")
+}
+
+//floods the menu with handler output, progress ticks and resizes, run with -race
+func TestRunUnderLoad(t *testing.T) {
+	h := New(t, tui.DefaultStyle())
+	defer h.Close()
+	h.Menu.Title = "Load"
+	h.Menu.Commands = []tui.Command{
+		tui.Command{
+			Title:    "Flood",
+			PrintOut: true,
+			Execute: func(c *tui.Command, ch chan string) {
+				defer close(ch)
+				for i := 0; i < 2000; i++ {
+					ch <- "."
+				}
+			},
+		},
+	}
+	h.Start()
+	for i := 0; i < 5; i++ {
+		h.Press(tcell.KeyEnter)
+		if !strings.Contains(h.Text(), strings.Repeat(".", 70)) {
+			t.Fatalf("output missing from result:\n%s", h.Text())
+		}
+		h.Resize(80-i, 25-i)
+		h.Press(tcell.KeyEscape)
+	}
+}
+
+//finds the position of a button in the bottom bar
+func buttonPos(t *testing.T, h *Harness, label string) (int, int) {
+	lines := strings.Split(strings.TrimRight(h.Text(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	x := strings.Index(last, "["+label+"]")
+	if x < 0 {
+		t.Fatalf("no %s button in %q", label, last)
+	}
+	return x + 1, len(lines) - 1
+}
+
+func TestMouse(t *testing.T) {
+	h := New(t, tui.DefaultStyle())
+	defer h.Close()
+	h.Menu.Title = "Mouse"
+	h.Menu.Mouse = true
+	for i := 0; i < 30; i++ {
+		h.Menu.Commands = append(h.Menu.Commands, tui.Command{
+			Title:    fmt.Sprintf("Count to %d", i*10),
+			Cli:      fmt.Sprintf("seq %d", i*10),
+			PrintOut: true,
+		})
+	}
+	h.Start()
+
+	//title takes the first two rows
+	h.Click(5, 3)
+	if h.Menu.Cursor != 1 {
+		t.Fatalf("click did not select, cursor %d", h.Menu.Cursor)
+	}
+	for i := 0; i < 25; i++ {
+		h.Mouse(5, 10, tcell.WheelDown)
+	}
+	h.Golden("mouse-wheel")
+
+	h.Click(5, 20)
+	h.Click(5, 20)
+	if !strings.Contains(h.Text(), "Success!") {
+		t.Fatalf("double click did not run:\n%s", h.Text())
+	}
+	h.Mouse(5, 10, tcell.WheelDown)
+	h.Golden("mouse-result-scrolled")
+
+	h.Click(buttonPos(t, h, "Back"))
+	h.Click(buttonPos(t, h, "Quit"))
+	if !h.Exited() {
+		t.Error("quit button did not exit")
+	}
+}
+
+//the menu buttons do nothing while a command runs
+func TestMouseWhileRunning(t *testing.T) {
+	h := New(t, tui.DefaultStyle())
+	defer h.Close()
+	h.Menu.Title = "Mouse"
+	h.Menu.Mouse = true
+	runs := 0
+	var clicks [][2]int
+	h.Menu.Commands = []tui.Command{
+		tui.Command{
+			Title:    "Click",
+			PrintOut: true,
+			Execute: func(c *tui.Command, ch chan string) {
+				defer close(ch)
+				runs++
+				for _, p := range clicks {
+					h.s.PostEventWait(tcell.NewEventMouse(p[0], p[1], tcell.Button1, tcell.ModNone))
+					h.s.PostEventWait(tcell.NewEventMouse(p[0], p[1], tcell.ButtonNone, tcell.ModNone))
+				}
+				ch <- "clicked"
+			},
+		},
+	}
+	h.Start()
+	for _, label := range []string{"Run", "Quit"} {
+		x, y := buttonPos(t, h, label)
+		clicks = append(clicks, [2]int{x, y})
+	}
+	h.Press(tcell.KeyEnter)
+	if h.Exited() {
+		t.Fatal("quit button exited while the command ran")
+	}
+	if runs != 1 || !strings.Contains(h.Text(), "clicked") {
+		t.Errorf("command ran %d times, screen:\n%s", runs, h.Text())
+	}
+}
+
+//without a bottom bar the views draw no buttons on the last row
+func TestMouseNoBottomBar(t *testing.T) {
+	h := newTestMenu(t)
+	defer h.Close()
+	h.Menu.Mouse = true
+	h.Menu.BottomBar = false
+	h.Start()
+	h.Press(tcell.KeyDown, tcell.KeyEnter)
+	if strings.Contains(h.Text(), "[Back]") {
+		t.Fatalf("button drawn without a bottom bar:\n%s", h.Text())
+	}
+	w, ht := h.Screen.Size()
+	h.Click(w-4, ht-1)
+	if !strings.Contains(h.Text(), "Please say hi") {
+		t.Errorf("click on the last row went back:\n%s", h.Text())
+	}
+}
+
+func TestHelp(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+	h.Press(tcell.KeyDown)
+	h.Type("?")
+	h.Golden("help-menu")
+	h.Press(tcell.KeyEscape)
+	if h.Exited() {
+		t.Fatal("closing the help exited the menu")
+	}
+
+	h.Press(tcell.KeyEnter)
+	h.Type("?")
+	if strings.Contains(h.Text(), "Help") {
+		t.Fatal("? opened the help while typing a value")
+	}
+	h.Press(tcell.KeyF1)
+	h.Golden("help-argument")
+	h.Press(tcell.KeyEnter)
+	if !strings.Contains(h.Text(), "?"+string(tcell.RuneBlock)) {
+		t.Errorf("value lost after closing the help:\n%s", h.Text())
+	}
+}
+
+func TestHelpNarrow(t *testing.T) {
+	for _, vi := range []bool{false, true} {
+		for _, width := range []int{28, 36, 40} {
+			h := newTestMenu(t)
+			if vi {
+				h.Menu.Keymap = tui.ViKeymap()
+			}
+			h.Start()
+			h.Resize(width, 40)
+			h.Press(tcell.KeyF1)
+			text := h.Text()
+			if !strings.Contains(text, "Help") || !strings.Contains(text, "exit") {
+				t.Errorf("help not shown at %d columns:\n%s", width, text)
+			}
+			h.Close()
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+)
+
+//Workflow commands are lines written by a command run with the OSCmdHandler that start with ::
+//They are hidden from the output and emitted as events instead, similar to CI workflow commands:
+//  ::progress 40               40 percent done, also ::progress 30/120 Copying files
+//  ::status Migrating          sets the Status of the command
+//  ::set version=1.2.3         sets a result of the command
+//  ::error file=a.go,line=3::Missing value
+//                              a log line, also ::debug, ::notice and ::warning
+//Lines starting with :: that are not a known command are printed as usual
+var workflowLevels = map[string]LogLevel{
+	"debug":   LogDebug,
+	"notice":  LogInfo,
+	"info":    LogInfo,
+	"warning": LogWarn,
+	"warn":    LogWarn,
+	"error":   LogError,
+}
+
+//splits the output of a command into text sent to ch and workflow commands
+type workflowParser struct {
+	c    *Command
+	ch   chan string
+	line string //start of a line that may be a command, held until it ends
+	mid  bool   //a line has been sent partially, the rest of it is text
+}
+
+//handles a chunk of output
+func (w *workflowParser) write(text string) {
+	out := ""
+	for text != "" {
+		part := text
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			part = text[:i+1]
+		}
+		text = text[len(part):]
+		ended := strings.HasSuffix(part, "\n")
+		if w.mid {
+			out += part
+			w.mid = !ended
+			continue
+		}
+		line := w.line + part
+		w.line = ""
+		if !strings.HasPrefix(line, "::") && !strings.HasPrefix("::", line) {
+			out += line
+			w.mid = !ended
+			continue
+		}
+		if !ended {
+			w.line = line
+			continue
+		}
+		if out != "" {
+			w.ch <- out
+			out = ""
+		}
+		if !w.c.workflowCommand(strings.TrimRight(line, "\r\n")) {
+			out = line
+		}
+	}
+	if out != "" {
+		w.ch <- out
+	}
+}
+
+//sends a line held when the output ends
+func (w *workflowParser) flush() {
+	if w.line != "" {
+		w.ch <- w.line
+		w.line = ""
+	}
+}
+
+//emits the event of a workflow command, returns false if line is not a known command
+func (c *Command) workflowCommand(line string) bool {
+	if !strings.HasPrefix(line, "::") {
+		return false
+	}
+	name, rest := line[2:], ""
+	if i := strings.IndexAny(name, " :"); i >= 0 {
+		name, rest = name[:i], strings.TrimPrefix(name[i:], " ")
+	}
+	switch name {
+	case "progress":
+		return c.workflowProgress(rest)
+	case "status":
+		c.SetStatus(rest)
+		return true
+	case "set":
+		i := strings.Index(rest, "=")
+		if i <= 0 {
+			return false
+		}
+		c.SetResult(strings.TrimSpace(rest[:i]), rest[i+1:])
+		return true
+	}
+	level, ok := workflowLevels[name]
+	if !ok {
+		return false
+	}
+	params, msg := "", rest
+	if i := strings.Index(rest, "::"); i >= 0 {
+		params, msg = rest[:i], rest[i+2:]
+	}
+	c.Log(level, workflowLocation(params)+msg)
+	return true
+}
+
+//emits the progress of ::progress percent [text] or ::progress done/total [text]
+func (c *Command) workflowProgress(rest string) bool {
+	fields := strings.SplitN(rest, " ", 2)
+	text := ""
+	if len(fields) == 2 {
+		text = strings.TrimSpace(fields[1])
+	}
+	parts := strings.SplitN(strings.TrimSuffix(fields[0], "%"), "/", 2)
+	if len(parts) == 1 {
+		percent, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return false
+		}
+		c.ProgressPercent(percent, text)
+		return true
+	}
+	done, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return false
+	}
+	total, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return false
+	}
+	c.Progress(done, total, text)
+	return true
+}
+
+//gets the file:line:col: prefix of a log line from parameters like file=a.go,line=3,col=2
+func workflowLocation(params string) string {
+	values := map[string]string{}
+	for _, p := range strings.Split(params, ",") {
+		if i := strings.Index(p, "="); i > 0 {
+			values[strings.TrimSpace(p[:i])] = p[i+1:]
+		}
+	}
+	loc := values["file"]
+	if loc == "" {
+		return ""
+	}
+	for _, k := range []string{"line", "col"} {
+		if values[k] == "" {
+			break
+		}
+		loc += ":" + values[k]
+	}
+	return loc + ": "
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWorkflowParser(t *testing.T) {
+	c := Command{}
+	c.resetEvents()
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		w := &workflowParser{c: &c, ch: ch}
+		//commands split across chunks and text without a line end are handled
+		w.write("start\nprompt: ")
+		w.write("yes\n::prog")
+		w.write("ress 30/120 Copying\n::status Copying\r\n")
+		w.write("::set version=1.2.3\n::error file=a.go,line=3::Missing value\n")
+		w.write("::unknown command\nend\n::progress half\n:")
+		w.flush()
+	}()
+	forward(ch, c.events, c.record)
+	out := ""
+	for _, e := range c.Events {
+		if e.Kind == EventStdout {
+			out += e.Text
+		}
+	}
+	if out != "start\nprompt: yes\n::unknown command\nend\n::progress half\n:" {
+		t.Errorf("unexpected output %q", out)
+	}
+	if c.Status != "Copying" {
+		t.Errorf("status not set: %q", c.Status)
+	}
+	if v, _ := c.Result("version"); v != "1.2.3" {
+		t.Errorf("result not set: %q", v)
+	}
+	var progress, log *Event
+	for i, e := range c.Events {
+		switch e.Kind {
+		case EventProgress:
+			progress = &c.Events[i]
+		case EventLog:
+			log = &c.Events[i]
+		}
+	}
+	if progress == nil || progress.Done != 30 || progress.Total != 120 || progress.Text != "Copying" {
+		t.Errorf("unexpected progress %v", progress)
+	}
+	if log == nil || log.Level != LogError || log.Text != "a.go:3: Missing value" {
+		t.Errorf("unexpected log %v", log)
+	}
+}
+
+func TestWorkflowCommands(t *testing.T) {
+	c := Command{
+		Cli:     "./sampleapp/testcommands/workflow.sh",
+		Execute: OSCmdHandler,
+	}
+	var out bytes.Buffer
+	if err := RunHeadless(&c, &out, &out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "::") {
+		t.Errorf("workflow commands printed: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "warn: db.sql:12: Table users has no index") {
+		t.Errorf("warning not printed: %q", out.String())
+	}
+	if v, _ := c.Result("version"); v != "1.2.3" || c.Status != "Migrated" {
+		t.Errorf("unexpected version %q and status %q", v, c.Status)
+	}
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"github.com/mattn/go-runewidth"
+)
+
+//Columns between tab stops
+const TabWidth = 8
+
+//a screen cell of printed text
+type cell struct {
+	r     rune
+	comb  []rune //zero width runes combined with r
+	width int
+	index int //index of the rune in the printed string, -1 for added runes
+}
+
+//splits a string in cells, expanding tabs to the next tab stop and keeping new lines
+//zero width runes are combined with the previous cell, other control characters are dropped
+func toCells(str string) []cell {
+	cells := []cell{}
+	col := 0
+	for n, r := range []rune(str) {
+		switch {
+		case r == '\n':
+			cells = append(cells, cell{r: r, index: n})
+			col = 0
+		case r == '\t':
+			for col++; ; col++ {
+				cells = append(cells, cell{r: ' ', width: 1, index: n})
+				if col%TabWidth == 0 {
+					break
+				}
+			}
+		case r < ' ':
+		case runewidth.RuneWidth(r) == 0:
+			if len(cells) == 0 || cells[len(cells)-1].r == '\n' {
+				cells = append(cells, cell{r: ' ', width: 1, index: n})
+				col++
+			}
+			last := &cells[len(cells)-1]
+			last.comb = append(last.comb, r)
+		default:
+			w := runewidth.RuneWidth(r)
+			cells = append(cells, cell{r: r, width: w, index: n})
+			col += w
+		}
+	}
+	return cells
+}
+
+//gets the columns taken by cells
+func cellsWidth(cells []cell) int {
+	w := 0
+	for _, c := range cells {
+		w += c.width
+	}
+	return w
+}
+
+//gets the columns of leading spaces
+func leadingSpaces(cells []cell) int {
+	n := 0
+	for n < len(cells) && cells[n].r == ' ' {
+		n++
+	}
+	return n
+}
+
+//splits cells in lines of up to width columns, breaking after words when possible
+//lines after the first are hang columns narrower, spaces at wrapped line ends are dropped
+func wrapCells(cells []cell, width, hang int) [][]cell {
+	lines := [][]cell{}
+	line := []cell{}
+	w, limit := 0, width
+	lastSpace := -1 //last space in line after some text
+	text := false   //line has text, leading spaces are not break points
+	wrapped := false
+	newLine := func(soft bool) {
+		lines = append(lines, line)
+		line = []cell{}
+		w, lastSpace, text, wrapped = 0, -1, false, soft
+		if soft {
+			limit = width - hang
+		} else {
+			limit = width
+		}
+	}
+	for _, c := range cells {
+		if c.r == '\n' {
+			newLine(false)
+			continue
+		}
+		if c.r == ' ' && wrapped && len(line) == 0 {
+			continue
+		}
+		if w+c.width > limit && len(line) > 0 {
+			switch {
+			case c.r == ' ':
+				newLine(true)
+				continue
+			case lastSpace >= 0:
+				rest := append([]cell{}, line[lastSpace+1:]...)
+				line = line[:lastSpace]
+				for len(line) > 0 && line[len(line)-1].r == ' ' {
+					line = line[:len(line)-1]
+				}
+				newLine(true)
+				line, w, text = rest, cellsWidth(rest), len(rest) > 0
+			default:
+				newLine(true)
+			}
+		}
+		if c.r == ' ' && text {
+			lastSpace = len(line)
+		} else if c.r != ' ' {
+			text = true
+		}
+		line = append(line, c)
+		w += c.width
+	}
+	return append(lines, line)
+}
+
+//cuts cells at the first new line or at width columns, ending with an ellipsis when cut
+//Nothing fits, not even the ellipsis, when width is not positive
+func truncateCells(cells []cell, width int) []cell {
+	if width <= 0 {
+		return []cell{}
+	}
+	cut := false
+	for i, c := range cells {
+		if c.r == '\n' {
+			cells, cut = cells[:i], true
+			break
+		}
+	}
+	if !cut && cellsWidth(cells) <= width {
+		return cells
+	}
+	line := []cell{}
+	w := 0
+	for _, c := range cells {
+		if w+c.width > width-1 {
+			break
+		}
+		line = append(line, c)
+		w += c.width
+	}
+	return append(line, cell{r: '…', width: 1, index: -1})
+}
+
+//splits text in lines of up to width columns, breaking after words when possible
+func wrapText(text string, width int) []string {
+	lines := []string{}
+	for _, line := range wrapCells(toCells(text), width, 0) {
+		str := []rune{}
+		for _, c := range line {
+			str = append(append(str, c.r), c.comb...)
+		}
+		lines = append(lines, string(str))
+	}
+	return lines
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func newTestPrinting(t *testing.T, w int) (*Printing, tcell.SimulationScreen) {
+	s := tcell.NewSimulationScreen("")
+	p, err := NewPrintingScreen(s, DefaultStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the screen takes the new size on Show
+	s.SetSize(w, 10)
+	p.Show()
+	return p, s
+}
+
+func printedLines(p *Printing, s tcell.SimulationScreen) []string {
+	p.Show()
+	lines := []string{}
+	for y := 0; y < p.Cursor; y++ {
+		lines = append(lines, screenLine(s, y))
+	}
+	return lines
+}
+
+func checkLines(t *testing.T, got []string, want ...string) {
+	if len(got) != len(want) {
+		t.Fatalf("got %q want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %q want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWrapWords(t *testing.T) {
+	p, s := newTestPrinting(t, 24)
+	defer s.Fini()
+	p.Putln("the quick brown fox jumps over the lazy dog", false)
+	checkLines(t, printedLines(p, s),
+		"  the quick brown fox",
+		"  jumps over the lazy",
+		"  dog")
+}
+
+func TestWrapHangingIndent(t *testing.T) {
+	p, s := newTestPrinting(t, 24)
+	defer s.Fini()
+	p.Putln("  - the quick brown fox jumps", false)
+	p.Hang = 2
+	p.Putln("- the quick brown fox jumps", false)
+	checkLines(t, printedLines(p, s),
+		"    - the quick brown",
+		"    fox jumps",
+		"  - the quick brown",
+		"    fox jumps")
+}
+
+func TestWrapLongWord(t *testing.T) {
+	p, s := newTestPrinting(t, 12)
+	defer s.Fini()
+	p.Putln("abcdefghijklmn op", false)
+	checkLines(t, printedLines(p, s),
+		"  abcdefgh",
+		"  ijklmn",
+		"  op")
+}
+
+func TestWrapTabsAndNewLines(t *testing.T) {
+	p, s := newTestPrinting(t, 40)
+	defer s.Fini()
+	p.Putln("a\tb\nab\tc", false)
+	checkLines(t, printedLines(p, s),
+		"  a       b",
+		"  ab      c")
+}
+
+func TestTruncate(t *testing.T) {
+	p, s := newTestPrinting(t, 16)
+	defer s.Fini()
+	p.Truncate = true
+	p.Putln("the quick brown fox", false)
+	p.Putln("short\nsecond", false)
+	p.Putln("fits exactly", false)
+	checkLines(t, printedLines(p, s),
+		"  the quick b…",
+		"  short…",
+		"  fits exactly")
+}
+
+func TestTruncateNoWidth(t *testing.T) {
+	for _, w := range []int{0, -3} {
+		if cells := truncateCells(toCells("the quick brown fox"), w); len(cells) != 0 {
+			t.Errorf("width %d: expected no cells, got %d", w, len(cells))
+		}
+	}
+}
+
+func TestWrapMarked(t *testing.T) {
+	p, s := newTestPrinting(t, 20)
+	defer s.Fini()
+	p.PutlnMarked("one\ttwo", false, map[int]bool{4: true})
+	p.Show()
+	if _, _, style, _ := s.GetContent(10, 0); style != p.style.Default.Underline(true) {
+		t.Error("marked rune not underlined after tab")
+	}
+}